@@ -0,0 +1,145 @@
+package jajson
+
+import "errors"
+
+// ErrorStop is returned by callbacks of ArrayEach and ObjectEach to stop the iteration without error
+var ErrorStop = errors.New("iteration stopped")
+
+// ArrayEach calls cb for every element of the array found by path
+func ArrayEach(data []byte, cb func(index int, typ LexemeType, value []byte) error, path ...string) error {
+	return defaultParser.ArrayEach(data, cb, path...)
+}
+
+// ArrayEach calls cb for every element of the array found by path in a single pass. Value is part of data,
+// Offset(data, value) returns its byte offset. Errors of cb positioned in value, for example those of getters
+// called on it, are moved to the offset in data, wrapping errors are kept.
+// The iteration stops at the first error, ErrorStop stops it and ArrayEach returns nil
+func (p *Parser) ArrayEach(data []byte, cb func(index int, typ LexemeType, value []byte) error, path ...string) error {
+	lex, err := p.enterContainer(data, path, OpenBracket)
+	if err != nil {
+		return err
+	}
+	lxm, err := lex.peek()
+	if err != nil {
+		return withPath(err, formatPath(path))
+	}
+	if lxm.Type == CloseBracket {
+		return nil
+	}
+	for index := 0; ; index++ {
+		typ, val, err := parseValue(lex)
+		if err != nil {
+			return withPath(err, formatPath(path))
+		}
+		if err := cb(index, typ, val); err != nil {
+			return eachError(err, data)
+		}
+		if closed, err := skipSeparator(lex, CloseBracket); err != nil || closed {
+			return withPath(err, formatPath(path))
+		}
+	}
+}
+
+// ObjectEach calls cb for every member of the object found by path
+func ObjectEach(data []byte, cb func(key []byte, typ LexemeType, value []byte) error, path ...string) error {
+	return defaultParser.ObjectEach(data, cb, path...)
+}
+
+// ObjectEach calls cb for every member of the object found by path in document order. Key is decoded,
+// it is part of data when there are no escapes and it is valid only until cb returns.
+// Errors and ErrorStop are handled like in ArrayEach
+func (p *Parser) ObjectEach(data []byte, cb func(key []byte, typ LexemeType, value []byte) error, path ...string) error {
+	lex, err := p.enterContainer(data, path, OpenCurve)
+	if err != nil {
+		return err
+	}
+	lxm, err := lex.next()
+	if err != nil {
+		return withPath(err, formatPath(path))
+	}
+	if lxm.Type == CloseCurve {
+		return nil
+	}
+	var buf [64]byte
+	for {
+		if !lex.isKey(lxm) {
+			return withPath(lex.lexemeError(ErrorUnexpectedLexeme, lxm), formatPath(path))
+		}
+		key, err := p.unescape(lxm.Value, buf[:0])
+		if err != nil {
+			return bindError(err, data, lxm.Value, formatPath(path))
+		}
+		if err := skipLexeme(lex, Colon); err != nil {
+			return withPath(err, formatPath(path))
+		}
+		typ, val, err := parseValue(lex)
+		if err != nil {
+			return withPath(err, formatPath(path))
+		}
+		if err := cb(key, typ, val); err != nil {
+			return eachError(err, data)
+		}
+		if closed, err := skipSeparator(lex, CloseCurve); err != nil || closed {
+			return withPath(err, formatPath(path))
+		}
+		if lxm, err = lex.next(); err != nil {
+			return withPath(err, formatPath(path))
+		}
+	}
+}
+
+// enterContainer returns tokenizer after the opening token of object or array found by path
+func (p *Parser) enterContainer(data []byte, path []string, open LexemeType) (*Tokenizer, error) {
+	if len(data) == 0 {
+		return nil, ErrorEmptyJSON
+	}
+	lex := p.NewTokenizer(data)
+	if err := skipPath(lex, path); err != nil {
+		return nil, withPath(err, formatPath(path))
+	}
+	if _, err := openContainer(lex, open); err != nil {
+		return nil, withPath(err, formatPath(path))
+	}
+	return lex, nil
+}
+
+// openContainer skips the opening token of object or array, ErrorWrongValueType is returned for other values
+func openContainer(lex *Tokenizer, open LexemeType) (Token, error) {
+	lxm, err := lex.next()
+	if err != nil {
+		return lxm, err
+	}
+	expected := Array
+	if open == OpenCurve {
+		expected = Object
+	}
+	switch lxm.Type {
+	case open:
+		return lxm, nil
+	case OpenCurve:
+		return lxm, lex.lexemeError(ErrorWrongValueType.withTypes(expected, Object), lxm)
+	case OpenBracket:
+		return lxm, lex.lexemeError(ErrorWrongValueType.withTypes(expected, Array), lxm)
+	case String, Int, Float, Bool, Null:
+		return lxm, lex.lexemeError(ErrorWrongValueType.withTypes(expected, lxm.Type), lxm)
+	}
+	return lxm, lex.lexemeError(ErrorUnexpectedLexeme, lxm)
+}
+
+// eachError returns nil for ErrorStop, Error positioned in part of data is moved to its position in data
+func eachError(err error, data []byte) error {
+	if errors.Is(err, ErrorStop) {
+		return nil
+	}
+	return inData(err, data)
+}
+
+// Offset returns the byte offset of part in data, for example of value passed to callback of ArrayEach.
+// It is -1 when part is empty or is not a subslice of data
+func Offset(data, part []byte) int {
+	offset := cap(data) - cap(part)
+	if len(part) == 0 || offset < 0 || offset+len(part) > len(data) || &data[offset] != &part[0] {
+		return -1
+	}
+	return offset
+}
@@ -0,0 +1,205 @@
+package jajson_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aleksandrzhukovskii/jajson"
+	"github.com/stretchr/testify/suite"
+)
+
+type EachSuite struct {
+	suite.Suite
+}
+
+func TestEach(t *testing.T) {
+	suite.Run(t, new(EachSuite))
+}
+
+func (t *EachSuite) TestArrayEach() {
+	data := []byte(`{"items": [1, "two", {"a": [3]}, [], null]}`)
+	var types []jajson.LexemeType
+	var values []string
+	var offsets []int
+	err := jajson.ArrayEach(data, func(index int, typ jajson.LexemeType, value []byte) error {
+		t.Equal(len(types), index)
+		types = append(types, typ)
+		values = append(values, string(value))
+		offsets = append(offsets, jajson.Offset(data, value))
+		return nil
+	}, "items")
+	t.Require().NoError(err)
+	t.Equal([]jajson.LexemeType{jajson.Int, jajson.String, jajson.Object, jajson.Array, jajson.Null}, types)
+	t.Equal([]string{`1`, `"two"`, `{"a": [3]}`, `[]`, `null`}, values)
+	t.Equal([]int{11, 14, 21, 33, 37}, offsets)
+
+	calls := 0
+	err = jajson.ArrayEach([]byte(` [ ] `), func(int, jajson.LexemeType, []byte) error {
+		calls++
+		return nil
+	})
+	t.NoError(err)
+	t.Zero(calls)
+}
+
+func (t *EachSuite) TestArrayEachStop() {
+	data := []byte(`[1, 2, 3, tru]`)
+	var values []string
+	err := jajson.ArrayEach(data, func(index int, _ jajson.LexemeType, value []byte) error {
+		values = append(values, string(value))
+		if index == 1 {
+			return jajson.ErrorStop
+		}
+		return nil
+	})
+	t.NoError(err)
+	t.Equal([]string{"1", "2"}, values)
+
+	custom := errors.New("custom")
+	err = jajson.ArrayEach(data, func(int, jajson.LexemeType, []byte) error {
+		return custom
+	})
+	t.ErrorIs(err, custom)
+}
+
+func (t *EachSuite) TestArrayEachErrors() {
+	data := []byte("{\"items\": [\n  {\"id\": 1},\n  {\"id\": \"x\"}\n]}")
+	err := jajson.ArrayEach(data, func(_ int, _ jajson.LexemeType, value []byte) error {
+		_, err := jajson.GetInt[int](value, "id")
+		return err
+	}, "items")
+	t.ErrorIs(err, jajson.ErrorNumberSyntax)
+	var e jajson.Error
+	t.Require().ErrorAs(err, &e)
+	t.Equal(3, e.Line())
+	t.Equal(10, e.Column())
+	t.Equal(34, e.BytePos())
+
+	custom := errors.New("custom")
+	err = jajson.ArrayEach(data, func(index int, _ jajson.LexemeType, value []byte) error {
+		if _, err := jajson.GetInt[int](value, "id"); err != nil {
+			return fmt.Errorf("item %d: %w: %w", index, err, custom)
+		}
+		return nil
+	}, "items")
+	t.ErrorIs(err, jajson.ErrorNumberSyntax)
+	t.ErrorIs(err, custom)
+	t.Require().ErrorAs(err, &e)
+	t.Equal(34, e.BytePos())
+	t.True(strings.HasPrefix(err.Error(), "item 1: Line: 3. Column: 10. Pos: 34. Path: id. Error: invalid number"), err.Error())
+
+	// errors positioned in other data are not moved
+	other := []byte(`{"q": true}`)
+	err = jajson.ArrayEach(data, func(int, jajson.LexemeType, []byte) error {
+		_, err := jajson.GetInt[int](other, "q")
+		return err
+	}, "items")
+	t.Require().ErrorAs(err, &e)
+	t.Equal(6, e.BytePos())
+	t.Equal("Line: 1. Column: 7. Pos: 6. Path: q. Expected: Int. Actual: Bool. Error: wrong type of value", err.Error())
+
+	noop := func(int, jajson.LexemeType, []byte) error { return nil }
+	err = jajson.ArrayEach([]byte(`{"items": {"a": 1}}`), noop, "items")
+	t.ErrorIs(err, jajson.ErrorWrongValueType)
+	t.Require().ErrorAs(err, &e)
+	t.Equal(jajson.Array, e.Expected())
+	t.Equal(jajson.Object, e.Actual())
+	t.Equal("items", e.Path())
+
+	t.ErrorIs(jajson.ArrayEach([]byte(`{"items": []}`), noop, "tags"), jajson.ErrorWrongPath)
+	t.ErrorIs(jajson.ArrayEach([]byte(`[1, 2`), noop), jajson.ErrorUnexpected)
+	t.ErrorIs(jajson.ArrayEach([]byte(`[1 2]`), noop), jajson.ErrorUnexpectedLexeme)
+	t.ErrorIs(jajson.ArrayEach([]byte(`[1,]`), noop), jajson.ErrorUnexpectedLexeme)
+	t.ErrorIs(jajson.ArrayEach(nil, noop), jajson.ErrorEmptyJSON)
+
+	p := jajson.Parser{TrailingCommas: true}
+	calls := 0
+	t.NoError(p.ArrayEach([]byte(`[1, 2,]`), func(int, jajson.LexemeType, []byte) error {
+		calls++
+		return nil
+	}))
+	t.Equal(2, calls)
+}
+
+func (t *EachSuite) TestObjectEach() {
+	data := []byte(`{"user": {"name": "a", "a\"b": 1, "ж": [2], "e": {}, "n": null}}`)
+	var keys, values []string
+	var types []jajson.LexemeType
+	err := jajson.ObjectEach(data, func(key []byte, typ jajson.LexemeType, value []byte) error {
+		keys = append(keys, string(key))
+		types = append(types, typ)
+		values = append(values, string(value))
+		return nil
+	}, "user")
+	t.Require().NoError(err)
+	t.Equal([]string{"name", `a"b`, "ж", "e", "n"}, keys)
+	t.Equal([]jajson.LexemeType{jajson.String, jajson.Int, jajson.Array, jajson.Object, jajson.Null}, types)
+	t.Equal([]string{`"a"`, `1`, `[2]`, `{}`, `null`}, values)
+
+	// keys without escapes are not copied
+	err = jajson.ObjectEach(data, func(key []byte, _ jajson.LexemeType, _ []byte) error {
+		t.Equal(11, jajson.Offset(data, key))
+		return jajson.ErrorStop
+	}, "user")
+	t.NoError(err)
+
+	calls := 0
+	t.NoError(jajson.ObjectEach([]byte(`{ }`), func([]byte, jajson.LexemeType, []byte) error {
+		calls++
+		return nil
+	}))
+	t.Zero(calls)
+}
+
+func (t *EachSuite) TestObjectEachJSON5() {
+	p := jajson.Parser{JSON5: true}
+	var keys []string
+	err := p.ObjectEach([]byte(`{a: 1, 'b\x41': 2, "c": 3,}`), func(key []byte, _ jajson.LexemeType, _ []byte) error {
+		keys = append(keys, string(key))
+		return nil
+	})
+	t.NoError(err)
+	t.Equal([]string{"a", "bA", "c"}, keys)
+}
+
+func (t *EachSuite) TestObjectEachErrors() {
+	noop := func([]byte, jajson.LexemeType, []byte) error { return nil }
+	err := jajson.ObjectEach([]byte(`{"a": [1]}`), noop, "a")
+	t.ErrorIs(err, jajson.ErrorWrongValueType)
+	var e jajson.Error
+	t.Require().ErrorAs(err, &e)
+	t.Equal(jajson.Object, e.Expected())
+	t.Equal(jajson.Array, e.Actual())
+
+	p := jajson.Parser{StrictSurrogates: true}
+	err = p.ObjectEach([]byte(`{"a": {"\ud800": 1}}`), noop, "a")
+	t.ErrorIs(err, jajson.ErrorLoneSurrogate)
+	t.Require().ErrorAs(err, &e)
+	t.Equal(7, e.Pos())
+	t.Equal("a", e.Path())
+
+	t.ErrorIs(jajson.ObjectEach([]byte(`{"a" 1}`), noop), jajson.ErrorUnexpectedLexeme)
+	t.ErrorIs(jajson.ObjectEach([]byte(`{1: 1}`), noop), jajson.ErrorUnexpectedLexeme)
+	t.ErrorIs(jajson.ObjectEach([]byte(`{"a": 1`), noop), jajson.ErrorUnexpected)
+
+	custom := errors.New("custom")
+	err = jajson.ObjectEach([]byte(`{"a": 1, "b": 2}`), func(key []byte, _ jajson.LexemeType, _ []byte) error {
+		if string(key) == "b" {
+			return custom
+		}
+		return nil
+	})
+	t.ErrorIs(err, custom)
+}
+
+func (t *EachSuite) TestOffset() {
+	data := []byte(`[1, 22]`)
+	t.Equal(0, jajson.Offset(data, data))
+	t.Equal(4, jajson.Offset(data, data[4:6]))
+	t.Equal(-1, jajson.Offset(data, []byte(`22`)))
+	t.Equal(-1, jajson.Offset(data[:3], data[4:6]))
+	t.Equal(-1, jajson.Offset(data, data[7:]))
+	t.Equal(-1, jajson.Offset(data[4:], data))
+}
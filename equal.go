@@ -0,0 +1,161 @@
+package jajson
+
+import (
+	"bytes"
+	"errors"
+	"math"
+)
+
+// equalValues reports whether two raw values are equal: numbers are compared by value, integers exactly
+// and the other ones as float64, strings after unescaping and object members regardless of their order
+func (p *Parser) equalValues(typA LexemeType, a []byte, typB LexemeType, b []byte) (bool, error) {
+	if isNumber(typA) && isNumber(typB) {
+		if bytes.Equal(a, b) {
+			return true, nil
+		} else if typA == Int && typB == Int {
+			return p.equalIntegers(a, b)
+		}
+		x, err := p.floatValue(a)
+		if err != nil {
+			return false, err
+		}
+		y, err := p.floatValue(b)
+		if err != nil {
+			return false, err
+		}
+		return x == y, nil
+	}
+	if typA != typB {
+		return false, nil
+	}
+	switch typA {
+	case String:
+		return p.equalStrings(a, b)
+	case Object:
+		return p.equalObjects(a, b)
+	case Array:
+		return p.equalArrays(a, b)
+	}
+	return bytes.Equal(a, b), nil
+}
+
+func (p *Parser) equalStrings(a, b []byte) (bool, error) {
+	var bufA, bufB [64]byte
+	x, err := p.unescape(a, bufA[:0])
+	if err != nil {
+		return false, err
+	}
+	y, err := p.unescape(b, bufB[:0])
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(x, y), nil
+}
+
+func (p *Parser) equalObjects(a, b []byte) (bool, error) {
+	_, membersA, err := parseChildren(p.NewTokenizer(a))
+	if err != nil {
+		return false, err
+	}
+	_, membersB, err := parseChildren(p.NewTokenizer(b))
+	if err != nil {
+		return false, err
+	}
+	if len(membersA) != len(membersB) {
+		return false, nil
+	}
+	byKey := make(map[string]child, len(membersB))
+	for _, member := range membersB {
+		key, err := p.unescape(member.key, nil)
+		if err != nil {
+			return false, err
+		}
+		byKey[string(key)] = member
+	}
+	for _, member := range membersA {
+		key, err := p.unescape(member.key, nil)
+		if err != nil {
+			return false, err
+		}
+		other, ok := byKey[string(key)]
+		if !ok {
+			return false, nil
+		}
+		if eq, err := p.equalValues(member.typ, member.value, other.typ, other.value); err != nil || !eq {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (p *Parser) equalArrays(a, b []byte) (bool, error) {
+	_, elementsA, err := parseChildren(p.NewTokenizer(a))
+	if err != nil {
+		return false, err
+	}
+	_, elementsB, err := parseChildren(p.NewTokenizer(b))
+	if err != nil {
+		return false, err
+	}
+	if len(elementsA) != len(elementsB) {
+		return false, nil
+	}
+	for i := range elementsA {
+		eq, err := p.equalValues(elementsA[i].typ, elementsA[i].value, elementsB[i].typ, elementsB[i].value)
+		if err != nil || !eq {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// equalIntegers compares integer literals of any size by their digits
+func (p *Parser) equalIntegers(a, b []byte) (bool, error) {
+	var bufA, bufB [24]byte
+	negA, x, err := p.integerDigits(a, bufA[:0])
+	if err != nil {
+		return false, err
+	}
+	negB, y, err := p.integerDigits(b, bufB[:0])
+	if err != nil {
+		return false, err
+	}
+	return negA == negB && bytes.Equal(x, y), nil
+}
+
+// integerDigits returns the sign and decimal digits of integer without leading zeros, zero has no digits
+func (p *Parser) integerDigits(num, buf []byte) (bool, []byte, error) {
+	if p.JSON5 && isHexSyntax(num) {
+		var err error
+		if num, err = hexDigits(num, buf); err != nil {
+			return false, nil, numberError(err)
+		}
+	}
+	neg := false
+	if len(num) > 0 && (num[0] == '-' || num[0] == '+') {
+		neg = num[0] == '-'
+		num = num[1:]
+	}
+	num = bytes.TrimLeft(num, "0")
+	return neg && len(num) > 0, num, nil
+}
+
+// floatValue returns the value of number for comparison, numbers out of range of float64 are ±Inf
+func (p *Parser) floatValue(num []byte) (float64, error) {
+	if p.JSON5 {
+		var buf [24]byte
+		var err error
+		if num, err = normalizeJSON5Number(num, buf[:0]); err != nil {
+			return 0, numberError(err)
+		}
+	}
+	f, err := parseFloat(num, 64)
+	if errors.Is(err, ErrorNumberRange) && math.IsInf(f, 0) {
+		return f, nil
+	}
+	return f, err
+}
+
+func isNumber(typ LexemeType) bool {
+	return typ == Int || typ == Float
+}
@@ -1,13 +1,57 @@
 package jajson
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
+// contextRadius is the maximum amount of runes around the error shown in its context
+const contextRadius = 32
+
+// ErrorCode identifies the kind of Error, codes are stable between versions
+type ErrorCode uint8
+
+const (
+	CodeUnexpected ErrorCode = iota + 1
+	CodeRune
+	CodeWrongQuote
+	CodeEmptyJSON
+	CodeUnexpectedLexeme
+	CodeWrongValueType
+	CodeWrongPath
+	CodeNull
+	CodeLoneSurrogate
+	CodeWrongPointer
+	CodeJSONPath
+	CodeTrailingData
+	CodeNumberSyntax
+	CodeNumberRange
+	CodePatchOperation
+	CodePatchTest
+)
+
+// Error is returned by all functions of the package. Errors with the same code match each other in errors.Is
 type Error struct {
-	pos int
-	err error
+	code    ErrorCode
+	pos     int
+	bytePos int
+	// src is the data the error is positioned in, line, column and context are computed from it on demand.
+	// It is a pointer, so Error stays comparable
+	src      *errorSource
+	path     string
+	expected LexemeType
+	actual   LexemeType
+	// expectedName replaces the name of expected type in the message, for example UInt of unsigned getters
+	expectedName string
+	// literal is the raw number which failed to convert
+	literal string
+	err     error
+	// cause is the wrapped error, for example *strconv.NumError
+	cause error
 }
 
 func (e Error) New(pos int) Error {
@@ -15,14 +59,319 @@ func (e Error) New(pos int) Error {
 	return e
 }
 
+// errorSource is the data of positioned Error
+type errorSource struct {
+	data []byte
+	// offset is the byte offset of the error in data
+	offset int
+	// lines and column are added to the line and column in data, column only on the first line of data
+	lines  int
+	column int
+}
+
+// lineStart returns the byte offset of the line of the error in data
+func (s *errorSource) lineStart() int {
+	return bytes.LastIndexByte(s.data[:s.offset], '\n') + 1
+}
+
+// at returns e positioned in src, pos is the rune offset and bytePos is the byte offset
+func (e Error) at(src []byte, pos, bytePos int) Error {
+	e.pos = pos
+	e.bytePos = bytePos
+	e.src = &errorSource{data: src, offset: bytePos}
+	return e
+}
+
+// errorContext returns the line around the offset with caret marker under it
+func errorContext(line []byte, offset int) string {
+	if end := bytes.IndexByte(line, '\n'); end >= 0 {
+		line = line[:end]
+	}
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	if offset > len(line) {
+		offset = len(line)
+	}
+	before, after := line[:offset], line[offset:]
+	for i, n := len(before), 0; i > 0; n++ {
+		if n == contextRadius {
+			before = before[i:]
+			break
+		}
+		_, size := utf8.DecodeLastRune(before[:i])
+		i -= size
+	}
+	for i, n := 0, 0; i < len(after); n++ {
+		if n == contextRadius {
+			after = after[:i]
+			break
+		}
+		_, size := utf8.DecodeRune(after[i:])
+		i += size
+	}
+	var sb strings.Builder
+	sb.Write(before)
+	sb.Write(after)
+	sb.WriteByte('\n')
+	for _, r := range string(before) {
+		if r == '\t' {
+			sb.WriteByte('\t')
+		} else {
+			sb.WriteByte(' ')
+		}
+	}
+	sb.WriteByte('^')
+	return sb.String()
+}
+
+// withTypes returns e with expected and actual types of value
+func (e Error) withTypes(expected, actual LexemeType) Error {
+	e.expected = expected
+	e.actual = actual
+	return e
+}
+
+// withNamedTypes returns e with expected and actual types of value, name replaces the name of expected type
+func (e Error) withNamedTypes(expected LexemeType, name string, actual LexemeType) Error {
+	e = e.withTypes(expected, actual)
+	e.expectedName = name
+	return e
+}
+
+// wrap returns e with the cause
+func (e Error) wrap(cause error) Error {
+	e.cause = cause
+	return e
+}
+
 func (e Error) Error() string {
-	return fmt.Sprintf("Pos: %d. Error: %s", e.pos, e.err.Error())
+	var sb strings.Builder
+	if e.src != nil {
+		fmt.Fprintf(&sb, "Line: %d. Column: %d. ", e.Line(), e.Column())
+	}
+	fmt.Fprintf(&sb, "Pos: %d. ", e.pos)
+	if e.path != "" {
+		fmt.Fprintf(&sb, "Path: %s. ", e.path)
+	}
+	if e.expected != nothing {
+		expected := e.expected.String()
+		if e.expectedName != "" {
+			expected = e.expectedName
+		}
+		fmt.Fprintf(&sb, "Expected: %s. Actual: %s. ", expected, e.actual)
+	}
+	fmt.Fprintf(&sb, "Error: %s", e.err.Error())
+	if e.cause != nil {
+		fmt.Fprintf(&sb, ": %s", e.cause.Error())
+	}
+	return sb.String()
+}
+
+// Is reports whether target is Error with the same code, so errors.Is(err, ErrorWrongPath) ignores position
+func (e Error) Is(target error) bool {
+	t, ok := target.(Error)
+	return ok && t.code != 0 && t.code == e.code
+}
+
+// Unwrap returns the wrapped error, for example *strconv.NumError
+func (e Error) Unwrap() error {
+	return e.cause
+}
+
+// Code returns the kind of the error
+func (e Error) Code() ErrorCode {
+	return e.code
+}
+
+// Path returns the requested path or pointer, it is empty when the error is not bound to the path
+func (e Error) Path() string {
+	return e.path
+}
+
+// Literal returns the raw number which failed to convert, for example the one out of range
+func (e Error) Literal() string {
+	return e.literal
+}
+
+// Expected returns the requested type of value, it is Int for unsigned getters reported as UInt in the message
+func (e Error) Expected() LexemeType {
+	return e.expected
+}
+
+// Actual returns the type of value found in JSON
+func (e Error) Actual() LexemeType {
+	return e.actual
+}
+
+// Pos returns the offset of the error in runes
+func (e Error) Pos() int {
+	return e.pos
+}
+
+// BytePos returns the offset of the error in bytes
+func (e Error) BytePos() int {
+	return e.bytePos
+}
+
+// Line returns 1-based line of the error, it is 0 when the error is not bound to the data
+func (e Error) Line() int {
+	if e.src == nil {
+		return 0
+	}
+	return bytes.Count(e.src.data[:e.src.offset], []byte{'\n'}) + 1 + e.src.lines
+}
+
+// Column returns 1-based column of the error in runes, it is 0 when the error is not bound to the data
+func (e Error) Column() int {
+	if e.src == nil {
+		return 0
+	}
+	lineStart := e.src.lineStart()
+	col := utf8.RuneCount(e.src.data[lineStart:e.src.offset]) + 1
+	if lineStart == 0 {
+		col += e.src.column
+	}
+	return col
+}
+
+// Context returns the line around the error with caret marker under the error position
+func (e Error) Context() string {
+	if e.src == nil {
+		return ""
+	}
+	lineStart := e.src.lineStart()
+	return errorContext(e.src.data[lineStart:], e.src.offset-lineStart)
+}
+
+// withPath adds the requested path to Error
+func withPath(err error, path string) error {
+	var e Error
+	if errors.As(err, &e) {
+		e.path = path
+		return e
+	}
+	return err
+}
+
+// atLine moves err positioned in a single line of input to the line of input. The line is copied,
+// because the buffer of the line is reused for the next one
+func atLine(err error, line int) error {
+	var e Error
+	if errors.As(err, &e) && e.src != nil {
+		src := *e.src
+		src.data = append([]byte(nil), src.data...)
+		src.lines += line - 1
+		e.src = &src
+		return e
+	}
+	return err
+}
+
+// numberError wraps errors of strconv into Error
+func numberError(err error) error {
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		return err
+	}
+	e := ErrorNumberSyntax
+	if errors.Is(numErr.Err, strconv.ErrRange) {
+		e = ErrorNumberRange
+	}
+	e = e.wrap(numErr)
+	e.literal = numErr.Num
+	return e
+}
+
+// bindError positions err of converting val at val in data and binds it to the requested path
+func bindError(err error, data, val []byte, path string) error {
+	var e Error
+	if !errors.As(err, &e) {
+		return err
+	}
+	if e.src == nil && val != nil && cap(val) <= cap(data) {
+		bytePos := cap(data) - cap(val)
+		e = e.at(data, utf8.RuneCount(data[:bytePos]), bytePos)
+	}
+	e.path = path
+	return e
+}
+
+// inData moves Error of err positioned in part of data to the position in data. Errors positioned in other
+// data are returned as is, errors wrapping Error are kept and report the moved position
+func inData(err error, data []byte) error {
+	var e Error
+	if !errors.As(err, &e) || e.src == nil {
+		return err
+	}
+	offset := Offset(data, e.src.data)
+	if offset < 0 {
+		return err
+	}
+	bytePos := offset + e.src.offset
+	moved := e.at(data, utf8.RuneCount(data[:bytePos]), bytePos)
+	if _, ok := err.(Error); ok {
+		return moved
+	}
+	return movedError{err: err, original: e, moved: moved}
+}
+
+// movedError is the error wrapping Error which is moved to the position in the whole data
+type movedError struct {
+	err      error
+	original Error
+	moved    Error
+}
+
+func (e movedError) Error() string {
+	return strings.Replace(e.err.Error(), e.original.Error(), e.moved.Error(), 1)
+}
+
+// Unwrap returns the moved Error before the original error, so errors.As finds the moved one
+func (e movedError) Unwrap() []error {
+	return []error{e.moved, e.err}
+}
+
+// formatPath returns human readable path, for example items[0].id or ["a.b"]
+func formatPath(path []string) string {
+	var sb strings.Builder
+	for i, part := range path {
+		if _, ok := pathIndex(part); ok {
+			sb.WriteString(part)
+		} else if isIdentifier(part) {
+			if i > 0 {
+				sb.WriteByte('.')
+			}
+			sb.WriteString(part)
+		} else {
+			sb.WriteByte('[')
+			sb.Write(appendQuote(nil, []byte(part)))
+			sb.WriteByte(']')
+		}
+	}
+	return sb.String()
+}
+
+func isIdentifier(str string) bool {
+	for i := 0; i < len(str); i++ {
+		if !(str[i] == '_' || isAlpha(rune(str[i])) || (i > 0 && isDigit(str[i]))) {
+			return false
+		}
+	}
+	return len(str) > 0
 }
 
-var ErrorUnexpected = Error{err: errors.New("unexpected symbol or end of JSON")}
-var ErrorRune = Error{err: errors.New("cannot parse next rune")}
-var ErrorWrongQuote = Error{err: errors.New("wrong quotation")}
-var ErrorEmptyJSON = Error{err: errors.New("JSON is empty")}
-var ErrorUnexpectedLexeme = Error{err: errors.New("unexpected lexeme while parsing JSON")}
-var ErrorWrongValueType = Error{err: errors.New("wrong type of value")}
-var ErrorWrongPath = Error{err: errors.New("path not found")}
+var ErrorUnexpected = Error{code: CodeUnexpected, err: errors.New("unexpected symbol or end of JSON")}
+var ErrorRune = Error{code: CodeRune, err: errors.New("cannot parse next rune")}
+var ErrorWrongQuote = Error{code: CodeWrongQuote, err: errors.New("wrong quotation")}
+var ErrorEmptyJSON = Error{code: CodeEmptyJSON, err: errors.New("JSON is empty")}
+var ErrorUnexpectedLexeme = Error{code: CodeUnexpectedLexeme, err: errors.New("unexpected lexeme while parsing JSON")}
+var ErrorWrongValueType = Error{code: CodeWrongValueType, err: errors.New("wrong type of value")}
+var ErrorWrongPath = Error{code: CodeWrongPath, err: errors.New("path not found")}
+var ErrorNull = Error{code: CodeNull, err: errors.New("value is null")}
+var ErrorLoneSurrogate = Error{code: CodeLoneSurrogate, err: errors.New("lone UTF-16 surrogate in string")}
+var ErrorWrongPointer = Error{code: CodeWrongPointer, err: errors.New("invalid JSON pointer")}
+var ErrorJSONPath = Error{code: CodeJSONPath, err: errors.New("invalid JSONPath expression")}
+var ErrorTrailingData = Error{code: CodeTrailingData, err: errors.New("unexpected data after JSON value")}
+var ErrorNumberSyntax = Error{code: CodeNumberSyntax, err: errors.New("invalid number")}
+var ErrorNumberRange = Error{code: CodeNumberRange, err: errors.New("number out of range")}
+var ErrorPatchOperation = Error{code: CodePatchOperation, err: errors.New("invalid JSON patch operation")}
+var ErrorPatchTest = Error{code: CodePatchTest, err: errors.New("JSON patch test failed")}
@@ -0,0 +1,123 @@
+package jajson_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/aleksandrzhukovskii/jajson"
+	"github.com/stretchr/testify/suite"
+)
+
+type ErrorsSuite struct {
+	suite.Suite
+}
+
+func TestErrors(t *testing.T) {
+	suite.Run(t, new(ErrorsSuite))
+}
+
+func (t *ErrorsSuite) TestIs() {
+	str := []byte(`{"items": [{"id": "x"}], "big": 300, "text": "abc", "n": null}`)
+
+	_, err := jajson.GetInt[int](str, "items", "[1]", "id")
+	t.ErrorIs(err, jajson.ErrorWrongPath)
+	t.NotErrorIs(err, jajson.ErrorWrongValueType)
+	var e jajson.Error
+	t.Require().ErrorAs(err, &e)
+	t.Equal(jajson.CodeWrongPath, e.Code())
+	t.Equal("items[1].id", e.Path())
+	t.Equal(1, e.Line())
+
+	_, err = jajson.GetInt[int](str, "items", "[0]", "id")
+	t.ErrorIs(err, jajson.ErrorNumberSyntax)
+	t.ErrorIs(err, strconv.ErrSyntax)
+	t.Require().ErrorAs(err, &e)
+	t.Equal(18, e.Pos())
+
+	_, err = jajson.GetInt[int8](str, "big")
+	t.ErrorIs(err, jajson.ErrorNumberRange)
+	t.ErrorIs(err, strconv.ErrRange)
+	var numErr *strconv.NumError
+	t.Require().ErrorAs(err, &numErr)
+	t.Equal("300", numErr.Num)
+	t.Require().ErrorAs(err, &e)
+	t.Equal("big", e.Path())
+	t.Equal(32, e.Pos())
+
+	_, err = jajson.GetBool(str, "text")
+	t.ErrorIs(err, jajson.ErrorWrongValueType)
+	t.Require().ErrorAs(err, &e)
+	t.Equal(jajson.Bool, e.Expected())
+	t.Equal(jajson.String, e.Actual())
+	t.Equal("text", e.Path())
+	t.Equal(`Line: 1. Column: 46. Pos: 45. Path: text. Expected: Bool. Actual: String. Error: wrong type of value`, err.Error())
+
+	_, err = jajson.GetFloat[float64](str, "n")
+	t.ErrorIs(err, jajson.ErrorNull)
+	t.Require().ErrorAs(err, &e)
+	t.Equal(jajson.Float, e.Expected())
+	t.Equal(jajson.Null, e.Actual())
+
+	_, err = jajson.GetUInt[uint](str, "items")
+	t.ErrorIs(err, jajson.ErrorWrongValueType)
+	t.Require().ErrorAs(err, &e)
+	t.Equal(jajson.Int, e.Expected())
+	t.Equal(`Line: 1. Column: 11. Pos: 10. Path: items. Expected: UInt. Actual: Array. Error: wrong type of value`, err.Error())
+
+	_, err = jajson.GetUInt[uint8](str, "n")
+	t.ErrorIs(err, jajson.ErrorNull)
+	t.Require().ErrorAs(err, &e)
+	t.Equal(jajson.Int, e.Expected())
+
+	_, err = jajson.GetString(str, "text", "inner")
+	t.ErrorIs(err, jajson.ErrorWrongPath)
+	t.Require().ErrorAs(err, &e)
+	t.Equal(jajson.Object, e.Expected())
+	t.Equal(jajson.String, e.Actual())
+	t.Equal("text.inner", e.Path())
+
+	_, err = jajson.GetStringByPointer(str, "/items/0/id/x")
+	t.ErrorIs(err, jajson.ErrorWrongPath)
+	t.Require().ErrorAs(err, &e)
+	t.Equal("/items/0/id/x", e.Path())
+
+	_, err = jajson.GetString([]byte(`{"a.b": 1}`), "a.b")
+	t.Require().ErrorAs(err, &e)
+	t.Equal(`["a.b"]`, e.Path())
+
+	t.False(errors.Is(err, jajson.Error{}))
+}
+
+func (t *ErrorsSuite) TestPosition() {
+	str := []byte("{\n  \"a\": 1,\n  \"b\": [true, nul]\n}")
+	_, err := jajson.GetBool(str, "b", "[1]")
+	var e jajson.Error
+	t.Require().ErrorAs(err, &e)
+	t.Equal(3, e.Line())
+	t.Equal(18, e.Column())
+	t.Equal(29, e.BytePos())
+	t.Equal("  \"b\": [true, nul]\n                 ^", e.Context())
+	t.True(err == error(e))
+
+	t.Equal(0, jajson.ErrorWrongPath.Line())
+	t.Equal(0, jajson.ErrorWrongPath.Column())
+	t.Empty(jajson.ErrorWrongPath.Context())
+}
+
+func (t *ErrorsSuite) TestKeyPosition() {
+	p := jajson.Parser{StrictSurrogates: true}
+	data := []byte("{\"a\": 1,\n \"\\ud800\": 2}")
+	_, err := p.GetInt(data, "b")
+	t.ErrorIs(err, jajson.ErrorLoneSurrogate)
+	var e jajson.Error
+	t.Require().ErrorAs(err, &e)
+	t.Equal(2, e.Line())
+	t.Equal(2, e.Column())
+	t.Equal(10, e.Pos())
+	t.Equal("b", e.Path())
+
+	values := p.GetValues(data, []string{"b"})
+	t.Require().ErrorAs(values[0].Err, &e)
+	t.Equal(2, e.Line())
+}
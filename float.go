@@ -0,0 +1,290 @@
+package jajson
+
+import (
+	"math"
+	"math/bits"
+	"strconv"
+)
+
+const (
+	minExp10 = -348
+	maxExp10 = 347
+	// maxMantDigits is the amount of decimal digits which always fit into uint64
+	maxMantDigits = 19
+)
+
+var exactPowersOfTen = [...]float64{1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10, 1e11,
+	1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19, 1e20, 1e21, 1e22}
+
+var exactPowersOfTen32 = [...]float32{1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10}
+
+// parseFloat converts the number into float of bitSize bits without allocation,
+// unusual literals like those with more than 19 significant digits fall back to strconv
+func parseFloat(num []byte, bitSize int) (float64, error) {
+	man, exp10, neg, trunc, ok := readFloat(num)
+	if ok {
+		if bitSize == 32 {
+			if f, ok := atof32(man, exp10, neg, trunc); ok {
+				return float64(f), nil
+			}
+		} else if f, ok := atof64(man, exp10, neg, trunc); ok {
+			return f, nil
+		}
+	}
+	f, err := strconv.ParseFloat(string(num), bitSize)
+	return f, numberError(err)
+}
+
+// readFloat reads decimal mantissa and exponent of the number, ok is false if it is not a decimal number
+func readFloat(num []byte) (man uint64, exp10 int, neg, trunc, ok bool) {
+	i := 0
+	if i < len(num) && num[i] == '-' {
+		neg = true
+		i++
+	}
+	sawDot, sawDigits := false, false
+	nd, ndMant, dp := 0, 0, 0
+loop:
+	for ; i < len(num); i++ {
+		switch c := num[i]; {
+		case c == '.':
+			if sawDot {
+				break loop
+			}
+			sawDot = true
+			dp = nd
+		case isDigit(c):
+			sawDigits = true
+			if c == '0' && nd == 0 {
+				// leading zeros are not significant
+				dp--
+				continue
+			}
+			nd++
+			if ndMant < maxMantDigits {
+				man = man*10 + uint64(c-'0')
+				ndMant++
+			} else if c != '0' {
+				trunc = true
+			}
+		default:
+			break loop
+		}
+	}
+	if !sawDigits {
+		return
+	}
+	if !sawDot {
+		dp = nd
+	}
+
+	if i < len(num) && (num[i] == 'e' || num[i] == 'E') {
+		i++
+		sign := 1
+		if i < len(num) && (num[i] == '+' || num[i] == '-') {
+			if num[i] == '-' {
+				sign = -1
+			}
+			i++
+		}
+		if i == len(num) || !isDigit(num[i]) {
+			return
+		}
+		e := 0
+		for ; i < len(num) && isDigit(num[i]); i++ {
+			if e < 10000 {
+				e = e*10 + int(num[i]-'0')
+			}
+		}
+		dp += e * sign
+	}
+	if man != 0 {
+		exp10 = dp - ndMant
+	}
+	ok = i == len(num)
+	return
+}
+
+func atof64(man uint64, exp10 int, neg, trunc bool) (float64, bool) {
+	if !trunc && man>>53 == 0 {
+		// both mantissa and power of ten are exact, so the single operation is correctly rounded
+		f := float64(man)
+		if neg {
+			f = -f
+		}
+		switch {
+		case exp10 == 0:
+			return f, true
+		case exp10 > 0 && exp10 <= 15+22:
+			exp := exp10
+			if exp > 22 {
+				f *= exactPowersOfTen[exp-22]
+				exp = 22
+			}
+			if f <= 1e15 && f >= -1e15 {
+				return f * exactPowersOfTen[exp], true
+			}
+		case exp10 < 0 && exp10 >= -22:
+			return f / exactPowersOfTen[-exp10], true
+		}
+	}
+	f, ok := eiselLemire64(man, exp10, neg)
+	if ok && trunc {
+		// the truncated mantissa is between man and man+1, both must round to the same float
+		f1, ok1 := eiselLemire64(man+1, exp10, neg)
+		ok = ok1 && f == f1
+	}
+	return f, ok
+}
+
+func atof32(man uint64, exp10 int, neg, trunc bool) (float32, bool) {
+	if !trunc && man>>24 == 0 {
+		f := float32(man)
+		if neg {
+			f = -f
+		}
+		switch {
+		case exp10 == 0:
+			return f, true
+		case exp10 > 0 && exp10 <= 7+10:
+			exp := exp10
+			if exp > 10 {
+				f *= exactPowersOfTen32[exp-10]
+				exp = 10
+			}
+			if f <= 1e7 && f >= -1e7 {
+				return f * exactPowersOfTen32[exp], true
+			}
+		case exp10 < 0 && exp10 >= -10:
+			return f / exactPowersOfTen32[-exp10], true
+		}
+	}
+	f, ok := eiselLemire32(man, exp10, neg)
+	if ok && trunc {
+		f1, ok1 := eiselLemire32(man+1, exp10, neg)
+		ok = ok1 && f == f1
+	}
+	return f, ok
+}
+
+// eiselLemire64 is the Eisel-Lemire algorithm, ok is false when the result cannot be decided
+// and the slow path is required
+func eiselLemire64(man uint64, exp10 int, neg bool) (float64, bool) {
+	if man == 0 {
+		if neg {
+			return math.Float64frombits(1 << 63), true
+		}
+		return 0, true
+	}
+	if exp10 < minExp10 || exp10 > maxExp10 {
+		return 0, false
+	}
+
+	// normalization
+	clz := bits.LeadingZeros64(man)
+	man <<= uint(clz)
+	const float64ExponentBias = 1023
+	retExp2 := uint64(217706*exp10>>16+64+float64ExponentBias) - uint64(clz)
+
+	// multiplication
+	pow := detailedPowersOfTen[exp10-minExp10]
+	xHi, xLo := bits.Mul64(man, pow[1])
+
+	// wider approximation
+	if xHi&0x1FF == 0x1FF && xLo+man < man {
+		yHi, yLo := bits.Mul64(man, pow[0])
+		mergedHi, mergedLo := xHi, xLo+yHi
+		if mergedLo < xLo {
+			mergedHi++
+		}
+		if mergedHi&0x1FF == 0x1FF && mergedLo+1 == 0 && yLo+man < man {
+			return 0, false
+		}
+		xHi, xLo = mergedHi, mergedLo
+	}
+
+	// shifting to 54 bits
+	msb := xHi >> 63
+	retMantissa := xHi >> (msb + 9)
+	retExp2 -= 1 ^ msb
+
+	// half-way ambiguity
+	if xLo == 0 && xHi&0x1FF == 0 && retMantissa&3 == 1 {
+		return 0, false
+	}
+
+	// from 54 to 53 bits
+	retMantissa += retMantissa & 1
+	retMantissa >>= 1
+	if retMantissa>>53 > 0 {
+		retMantissa >>= 1
+		retExp2++
+	}
+	// subnormal, infinite and NaN results are left to the slow path
+	if retExp2-1 >= 0x7FF-1 {
+		return 0, false
+	}
+	retBits := retExp2<<52 | retMantissa&(1<<52-1)
+	if neg {
+		retBits |= 1 << 63
+	}
+	return math.Float64frombits(retBits), true
+}
+
+// eiselLemire32 is eiselLemire64 for float32
+func eiselLemire32(man uint64, exp10 int, neg bool) (float32, bool) {
+	if man == 0 {
+		if neg {
+			return math.Float32frombits(1 << 31), true
+		}
+		return 0, true
+	}
+	if exp10 < minExp10 || exp10 > maxExp10 {
+		return 0, false
+	}
+
+	clz := bits.LeadingZeros64(man)
+	man <<= uint(clz)
+	const float32ExponentBias = 127
+	retExp2 := uint64(217706*exp10>>16+64+float32ExponentBias) - uint64(clz)
+
+	pow := detailedPowersOfTen[exp10-minExp10]
+	xHi, xLo := bits.Mul64(man, pow[1])
+
+	if xHi&0x3FFFFFFFFF == 0x3FFFFFFFFF && xLo+man < man {
+		yHi, yLo := bits.Mul64(man, pow[0])
+		mergedHi, mergedLo := xHi, xLo+yHi
+		if mergedLo < xLo {
+			mergedHi++
+		}
+		if mergedHi&0x3FFFFFFFFF == 0x3FFFFFFFFF && mergedLo+1 == 0 && yLo+man < man {
+			return 0, false
+		}
+		xHi, xLo = mergedHi, mergedLo
+	}
+
+	// shifting to 25 bits
+	msb := xHi >> 63
+	retMantissa := xHi >> (msb + 38)
+	retExp2 -= 1 ^ msb
+
+	if xLo == 0 && xHi&0x3FFFFFFFFF == 0 && retMantissa&3 == 1 {
+		return 0, false
+	}
+
+	// from 25 to 24 bits
+	retMantissa += retMantissa & 1
+	retMantissa >>= 1
+	if retMantissa>>24 > 0 {
+		retMantissa >>= 1
+		retExp2++
+	}
+	if retExp2-1 >= 0xFF-1 {
+		return 0, false
+	}
+	retBits := retExp2<<23 | retMantissa&(1<<23-1)
+	if neg {
+		retBits |= 1 << 31
+	}
+	return math.Float32frombits(uint32(retBits)), true
+}
@@ -0,0 +1,219 @@
+package jajson
+
+// Match is a value found by JSONPath query, Value is part of the original slice
+type Match struct {
+	Type  LexemeType
+	Value []byte
+}
+
+// JSONPath is a compiled RFC 9535 query
+type JSONPath struct {
+	expr     string
+	segments []jsonPathSegment
+}
+
+type jsonPathSegment struct {
+	descendant bool
+	selectors  []jsonPathSelector
+}
+
+type selectorKind uint8
+
+const (
+	selectorName selectorKind = iota
+	selectorWildcard
+	selectorIndex
+	selectorSlice
+	selectorFilter
+)
+
+type jsonPathSelector struct {
+	kind   selectorKind
+	name   []byte
+	index  int
+	slice  jsonPathSlice
+	filter *filterExpr
+}
+
+type jsonPathSlice struct {
+	start, end, step int
+	hasStart, hasEnd bool
+}
+
+// Query returns all values matching RFC 9535 JSONPath expression in document order
+func Query(data []byte, expr string) ([]Match, error) {
+	return defaultParser.Query(data, expr)
+}
+
+// QueryJSONPath returns all values matching compiled JSONPath query in document order
+func QueryJSONPath(data []byte, path *JSONPath) ([]Match, error) {
+	return defaultParser.QueryJSONPath(data, path)
+}
+
+// Query returns all values matching RFC 9535 JSONPath expression in document order
+func (p *Parser) Query(data []byte, expr string) ([]Match, error) {
+	path, err := CompileJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return p.QueryJSONPath(data, path)
+}
+
+// QueryJSONPath returns all values matching compiled JSONPath query in document order
+func (p *Parser) QueryJSONPath(data []byte, path *JSONPath) ([]Match, error) {
+	if len(data) == 0 {
+		return nil, ErrorEmptyJSON
+	}
+	typ, val, err := parseValue(p.NewTokenizer(data))
+	if err != nil {
+		return nil, err
+	}
+	root := Match{Type: typ, Value: val}
+	nodes, err := p.evalSegments(path.segments, root, []Match{root})
+	if err != nil {
+		return nil, withPath(err, path.expr)
+	}
+	return nodes, nil
+}
+
+// String returns the original expression
+func (path *JSONPath) String() string {
+	return path.expr
+}
+
+func (p *Parser) evalSegments(segments []jsonPathSegment, root Match, nodes []Match) ([]Match, error) {
+	for i := 0; i < len(segments) && len(nodes) > 0; i++ {
+		var next []Match
+		for _, node := range nodes {
+			var err error
+			if next, err = p.evalSegment(&segments[i], root, node, next); err != nil {
+				return nil, err
+			}
+		}
+		nodes = next
+	}
+	return nodes, nil
+}
+
+// evalSegment appends to ret the values selected from the node, descendant segment also visits all descendants
+func (p *Parser) evalSegment(segment *jsonPathSegment, root, node Match, ret []Match) ([]Match, error) {
+	if node.Type != Object && node.Type != Array {
+		return ret, nil
+	}
+	_, children, err := parseChildren(p.NewTokenizer(node.Value))
+	if err != nil {
+		return nil, err
+	}
+	for i := range segment.selectors {
+		if ret, err = p.evalSelector(&segment.selectors[i], root, node.Type, children, ret); err != nil {
+			return nil, err
+		}
+	}
+	if segment.descendant {
+		for _, c := range children {
+			if ret, err = p.evalSegment(segment, root, Match{Type: c.typ, Value: c.value}, ret); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ret, nil
+}
+
+func (p *Parser) evalSelector(sel *jsonPathSelector, root Match, typ LexemeType, children []child, ret []Match) ([]Match, error) {
+	switch sel.kind {
+	case selectorName:
+		if typ != Object {
+			return ret, nil
+		}
+		var buf [64]byte
+		for _, c := range children {
+			key, err := p.unescape(c.key, buf[:0])
+			if err != nil {
+				return nil, err
+			}
+			if string(key) == string(sel.name) {
+				ret = append(ret, Match{Type: c.typ, Value: c.value})
+			}
+		}
+	case selectorWildcard:
+		for _, c := range children {
+			ret = append(ret, Match{Type: c.typ, Value: c.value})
+		}
+	case selectorIndex:
+		if typ != Array {
+			return ret, nil
+		}
+		index := sel.index
+		if index < 0 {
+			index += len(children)
+		}
+		if index >= 0 && index < len(children) {
+			ret = append(ret, Match{Type: children[index].typ, Value: children[index].value})
+		}
+	case selectorSlice:
+		if typ != Array {
+			return ret, nil
+		}
+		lower, upper, step := sel.slice.bounds(len(children))
+		if step > 0 {
+			for i := lower; i < upper; i += step {
+				ret = append(ret, Match{Type: children[i].typ, Value: children[i].value})
+			}
+		} else if step < 0 {
+			for i := upper; lower < i; i += step {
+				ret = append(ret, Match{Type: children[i].typ, Value: children[i].value})
+			}
+		}
+	case selectorFilter:
+		for _, c := range children {
+			node := Match{Type: c.typ, Value: c.value}
+			ok, err := p.evalFilter(sel.filter, root, node)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				ret = append(ret, node)
+			}
+		}
+	}
+	return ret, nil
+}
+
+// bounds returns the range of slice indices according to RFC 9535 section 2.3.4.2.2,
+// for negative step elements from upper down to but excluding lower are selected
+func (s jsonPathSlice) bounds(length int) (int, int, int) {
+	step := s.step
+	if step == 0 {
+		return 0, 0, 0
+	}
+	start, end := 0, length
+	if step < 0 {
+		start, end = length-1, -length-1
+	}
+	if s.hasStart {
+		start = s.start
+	}
+	if s.hasEnd {
+		end = s.end
+	}
+	normalize := func(i int) int {
+		if i >= 0 {
+			return i
+		}
+		return length + i
+	}
+	start, end = normalize(start), normalize(end)
+	if step > 0 {
+		return clamp(start, 0, length), clamp(end, 0, length), step
+	}
+	return clamp(end, -1, length-1), clamp(start, -1, length-1), step
+}
+
+func clamp(i, low, high int) int {
+	if i < low {
+		return low
+	} else if i > high {
+		return high
+	}
+	return i
+}
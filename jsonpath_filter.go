@@ -0,0 +1,170 @@
+package jajson
+
+import (
+	"bytes"
+)
+
+type filterOp uint8
+
+const (
+	filterOr filterOp = iota
+	filterAnd
+	filterNot
+	filterExists
+	filterEqual
+	filterNotEqual
+	filterLess
+	filterLessEqual
+	filterGreater
+	filterGreaterEqual
+)
+
+var compareOps = []struct {
+	token string
+	op    filterOp
+}{
+	{"==", filterEqual}, {"!=", filterNotEqual}, {"<=", filterLessEqual}, {">=", filterGreaterEqual},
+	{"<", filterLess}, {">", filterGreater},
+}
+
+type filterExpr struct {
+	op filterOp
+	// left and right are operands of logical operators, not uses only left
+	left, right *filterExpr
+	// query is checked for existence of values
+	query *filterQuery
+	// lhs and rhs are operands of comparison
+	lhs, rhs filterOperand
+}
+
+// filterOperand is either a query or a literal
+type filterOperand struct {
+	query   *filterQuery
+	literal Match
+}
+
+type filterQuery struct {
+	// relative query starts from the current node @, otherwise from the root $
+	relative bool
+	segments []jsonPathSegment
+}
+
+// singular reports whether the query can select at most one value
+func (q *filterQuery) singular() bool {
+	for _, segment := range q.segments {
+		if segment.descendant || len(segment.selectors) != 1 {
+			return false
+		}
+		if kind := segment.selectors[0].kind; kind != selectorName && kind != selectorIndex {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Parser) evalFilter(e *filterExpr, root, cur Match) (bool, error) {
+	switch e.op {
+	case filterOr, filterAnd:
+		left, err := p.evalFilter(e.left, root, cur)
+		if err != nil {
+			return false, err
+		}
+		if left == (e.op == filterOr) {
+			return left, nil
+		}
+		return p.evalFilter(e.right, root, cur)
+	case filterNot:
+		ret, err := p.evalFilter(e.left, root, cur)
+		return !ret, err
+	case filterExists:
+		nodes, err := p.evalQuery(e.query, root, cur)
+		return len(nodes) > 0, err
+	}
+
+	lhs, err := p.evalOperand(e.lhs, root, cur)
+	if err != nil {
+		return false, err
+	}
+	rhs, err := p.evalOperand(e.rhs, root, cur)
+	if err != nil {
+		return false, err
+	}
+	switch e.op {
+	case filterEqual:
+		return p.equalOperands(lhs, rhs)
+	case filterNotEqual:
+		eq, err := p.equalOperands(lhs, rhs)
+		return !eq, err
+	case filterLess:
+		return p.lessOperands(lhs, rhs)
+	case filterGreater:
+		return p.lessOperands(rhs, lhs)
+	case filterLessEqual:
+		return p.lessOrEqualOperands(lhs, rhs)
+	case filterGreaterEqual:
+		return p.lessOrEqualOperands(rhs, lhs)
+	}
+	return false, nil
+}
+
+func (p *Parser) evalQuery(q *filterQuery, root, cur Match) ([]Match, error) {
+	if q.relative {
+		return p.evalSegments(q.segments, root, []Match{cur})
+	}
+	return p.evalSegments(q.segments, root, []Match{root})
+}
+
+// evalOperand returns the value of operand, Type of the result is nothing when singular query selects nothing
+func (p *Parser) evalOperand(o filterOperand, root, cur Match) (Match, error) {
+	if o.query == nil {
+		return o.literal, nil
+	}
+	nodes, err := p.evalQuery(o.query, root, cur)
+	if err != nil || len(nodes) == 0 {
+		return Match{}, err
+	}
+	return nodes[0], nil
+}
+
+func (p *Parser) equalOperands(a, b Match) (bool, error) {
+	if a.Type == nothing || b.Type == nothing {
+		return a.Type == b.Type, nil
+	}
+	return p.equalValues(a.Type, a.Value, b.Type, b.Value)
+}
+
+// lessOperands compares numbers and strings, all other values are not ordered
+func (p *Parser) lessOperands(a, b Match) (bool, error) {
+	if isNumber(a.Type) && isNumber(b.Type) {
+		x, err := p.floatValue(a.Value)
+		if err != nil {
+			return false, err
+		}
+		y, err := p.floatValue(b.Value)
+		if err != nil {
+			return false, err
+		}
+		return x < y, nil
+	}
+	if a.Type == String && b.Type == String {
+		var bufA, bufB [64]byte
+		x, err := p.unescape(a.Value, bufA[:0])
+		if err != nil {
+			return false, err
+		}
+		y, err := p.unescape(b.Value, bufB[:0])
+		if err != nil {
+			return false, err
+		}
+		return bytes.Compare(x, y) < 0, nil
+	}
+	return false, nil
+}
+
+func (p *Parser) lessOrEqualOperands(a, b Match) (bool, error) {
+	less, err := p.lessOperands(a, b)
+	if err != nil || less {
+		return less, err
+	}
+	return p.equalOperands(a, b)
+}
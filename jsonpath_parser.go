@@ -0,0 +1,375 @@
+package jajson
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// maxJSONPathInt is the I-JSON range of integers allowed in indices and slices
+const maxJSONPathInt = 1<<53 - 1
+
+type jsonPathParser struct {
+	expr string
+	pos  int
+}
+
+// CompileJSONPath parses RFC 9535 JSONPath expression. Function extensions are not supported
+func CompileJSONPath(expr string) (*JSONPath, error) {
+	jp := jsonPathParser{expr: expr}
+	if !jp.consume("$") {
+		return nil, jp.error()
+	}
+	segments, err := jp.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	if jp.pos != len(expr) {
+		return nil, jp.error()
+	}
+	return &JSONPath{expr: expr, segments: segments}, nil
+}
+
+func (jp *jsonPathParser) error() error {
+	return withPath(ErrorJSONPath.New(jp.pos), jp.expr)
+}
+
+func (jp *jsonPathParser) peek() byte {
+	if jp.pos < len(jp.expr) {
+		return jp.expr[jp.pos]
+	}
+	return 0
+}
+
+func (jp *jsonPathParser) consume(token string) bool {
+	if strings.HasPrefix(jp.expr[jp.pos:], token) {
+		jp.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (jp *jsonPathParser) skipSpaces() {
+	for jp.pos < len(jp.expr) {
+		switch jp.expr[jp.pos] {
+		case ' ', '\t', '\n', '\r':
+			jp.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (jp *jsonPathParser) parseSegments() ([]jsonPathSegment, error) {
+	var segments []jsonPathSegment
+	for {
+		start := jp.pos
+		jp.skipSpaces()
+		var segment jsonPathSegment
+		var err error
+		switch {
+		case jp.consume(".."):
+			if jp.peek() == '[' {
+				segment, err = jp.parseBracket()
+			} else {
+				segment, err = jp.parseShorthand()
+			}
+			segment.descendant = true
+		case jp.consume("."):
+			segment, err = jp.parseShorthand()
+		case jp.peek() == '[':
+			segment, err = jp.parseBracket()
+		default:
+			jp.pos = start
+			return segments, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, segment)
+	}
+}
+
+// parseShorthand parses wildcard or member name after dot
+func (jp *jsonPathParser) parseShorthand() (jsonPathSegment, error) {
+	if jp.consume("*") {
+		return jsonPathSegment{selectors: []jsonPathSelector{{kind: selectorWildcard}}}, nil
+	}
+	start := jp.pos
+	for jp.pos < len(jp.expr) {
+		r, size := utf8.DecodeRuneInString(jp.expr[jp.pos:])
+		if r == utf8.RuneError || !(r == '_' || r >= utf8.RuneSelf || isAlpha(r) || (jp.pos > start && isDigit(byte(r)))) {
+			break
+		}
+		jp.pos += size
+	}
+	if jp.pos == start {
+		return jsonPathSegment{}, jp.error()
+	}
+	name := []byte(jp.expr[start:jp.pos])
+	return jsonPathSegment{selectors: []jsonPathSelector{{kind: selectorName, name: name}}}, nil
+}
+
+func (jp *jsonPathParser) parseBracket() (jsonPathSegment, error) {
+	var segment jsonPathSegment
+	jp.pos++
+	for {
+		jp.skipSpaces()
+		sel, err := jp.parseSelector()
+		if err != nil {
+			return segment, err
+		}
+		segment.selectors = append(segment.selectors, sel)
+		jp.skipSpaces()
+		if jp.consume("]") {
+			return segment, nil
+		} else if !jp.consume(",") {
+			return segment, jp.error()
+		}
+	}
+}
+
+func (jp *jsonPathParser) parseSelector() (jsonPathSelector, error) {
+	switch c := jp.peek(); {
+	case c == '\'' || c == '"':
+		name, err := jp.parseString()
+		return jsonPathSelector{kind: selectorName, name: name}, err
+	case c == '*':
+		jp.pos++
+		return jsonPathSelector{kind: selectorWildcard}, nil
+	case c == '?':
+		jp.pos++
+		jp.skipSpaces()
+		filter, err := jp.parseOr()
+		return jsonPathSelector{kind: selectorFilter, filter: filter}, err
+	}
+
+	slice := jsonPathSlice{step: 1}
+	var err error
+	if c := jp.peek(); c == '-' || isDigit(c) {
+		if slice.start, err = jp.parseInt(); err != nil {
+			return jsonPathSelector{}, err
+		}
+		slice.hasStart = true
+	}
+	start := jp.pos
+	jp.skipSpaces()
+	if !jp.consume(":") {
+		jp.pos = start
+		if !slice.hasStart {
+			return jsonPathSelector{}, jp.error()
+		}
+		return jsonPathSelector{kind: selectorIndex, index: slice.start}, nil
+	}
+	jp.skipSpaces()
+	if c := jp.peek(); c == '-' || isDigit(c) {
+		if slice.end, err = jp.parseInt(); err != nil {
+			return jsonPathSelector{}, err
+		}
+		slice.hasEnd = true
+		jp.skipSpaces()
+	}
+	if jp.consume(":") {
+		jp.skipSpaces()
+		if c := jp.peek(); c == '-' || isDigit(c) {
+			if slice.step, err = jp.parseInt(); err != nil {
+				return jsonPathSelector{}, err
+			}
+		}
+	}
+	return jsonPathSelector{kind: selectorSlice, slice: slice}, nil
+}
+
+// parseInt parses integer without leading zeros in I-JSON range
+func (jp *jsonPathParser) parseInt() (int, error) {
+	start := jp.pos
+	neg := jp.consume("-")
+	digits := jp.pos
+	for jp.pos < len(jp.expr) && isDigit(jp.expr[jp.pos]) {
+		jp.pos++
+	}
+	str := jp.expr[digits:jp.pos]
+	if len(str) == 0 || (str[0] == '0' && (len(str) > 1 || neg)) || len(str) > 16 {
+		jp.pos = start
+		return 0, jp.error()
+	}
+	n := 0
+	for i := 0; i < len(str); i++ {
+		n = n*10 + int(str[i]-'0')
+	}
+	if n > maxJSONPathInt {
+		jp.pos = start
+		return 0, jp.error()
+	}
+	if neg {
+		return -n, nil
+	}
+	return n, nil
+}
+
+// parseString parses single or double quoted string literal and returns it decoded
+func (jp *jsonPathParser) parseString() ([]byte, error) {
+	quote := jp.expr[jp.pos]
+	start := jp.pos
+	jp.pos++
+	literal := []byte{'"'}
+	for {
+		if jp.pos >= len(jp.expr) {
+			jp.pos = start
+			return nil, jp.error()
+		}
+		c := jp.expr[jp.pos]
+		switch {
+		case c == quote:
+			jp.pos++
+			literal = append(literal, '"')
+			ret, err := unescape(literal, nil, false, false, false)
+			if err != nil {
+				jp.pos = start
+				return nil, jp.error()
+			}
+			return ret, nil
+		case c < 0x20:
+			return nil, jp.error()
+		case c == '"':
+			literal = append(literal, '\\', '"')
+		case c == '\\' && jp.pos+1 < len(jp.expr) && jp.expr[jp.pos+1] == '\'' && quote == '\'':
+			literal = append(literal, '\'')
+			jp.pos++
+		case c == '\\' && jp.pos+1 < len(jp.expr):
+			literal = append(literal, c, jp.expr[jp.pos+1])
+			jp.pos++
+		default:
+			literal = append(literal, c)
+		}
+		jp.pos++
+	}
+}
+
+func (jp *jsonPathParser) parseOr() (*filterExpr, error) {
+	left, err := jp.parseAnd()
+	for err == nil {
+		start := jp.pos
+		jp.skipSpaces()
+		if !jp.consume("||") {
+			jp.pos = start
+			return left, nil
+		}
+		jp.skipSpaces()
+		var right *filterExpr
+		right, err = jp.parseAnd()
+		left = &filterExpr{op: filterOr, left: left, right: right}
+	}
+	return nil, err
+}
+
+func (jp *jsonPathParser) parseAnd() (*filterExpr, error) {
+	left, err := jp.parseBasic()
+	for err == nil {
+		start := jp.pos
+		jp.skipSpaces()
+		if !jp.consume("&&") {
+			jp.pos = start
+			return left, nil
+		}
+		jp.skipSpaces()
+		var right *filterExpr
+		right, err = jp.parseBasic()
+		left = &filterExpr{op: filterAnd, left: left, right: right}
+	}
+	return nil, err
+}
+
+// parseBasic parses parenthesized expression, comparison or existence test
+func (jp *jsonPathParser) parseBasic() (*filterExpr, error) {
+	if jp.consume("!") {
+		jp.skipSpaces()
+		var expr *filterExpr
+		var err error
+		if jp.peek() == '(' {
+			expr, err = jp.parseParen()
+		} else if c := jp.peek(); c == '@' || c == '$' {
+			var operand filterOperand
+			operand, err = jp.parseOperand()
+			expr = &filterExpr{op: filterExists, query: operand.query}
+		} else {
+			err = jp.error()
+		}
+		return &filterExpr{op: filterNot, left: expr}, err
+	}
+	if jp.peek() == '(' {
+		return jp.parseParen()
+	}
+
+	lhs, err := jp.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	start := jp.pos
+	jp.skipSpaces()
+	op, ok := jp.parseCompareOp()
+	if !ok {
+		jp.pos = start
+		if lhs.query == nil {
+			return nil, jp.error()
+		}
+		return &filterExpr{op: filterExists, query: lhs.query}, nil
+	}
+	jp.skipSpaces()
+	rhs, err := jp.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if (lhs.query != nil && !lhs.query.singular()) || (rhs.query != nil && !rhs.query.singular()) {
+		return nil, jp.error()
+	}
+	return &filterExpr{op: op, lhs: lhs, rhs: rhs}, nil
+}
+
+func (jp *jsonPathParser) parseParen() (*filterExpr, error) {
+	jp.pos++
+	jp.skipSpaces()
+	expr, err := jp.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	jp.skipSpaces()
+	if !jp.consume(")") {
+		return nil, jp.error()
+	}
+	return expr, nil
+}
+
+func (jp *jsonPathParser) parseCompareOp() (filterOp, bool) {
+	for _, op := range compareOps {
+		if jp.consume(op.token) {
+			return op.op, true
+		}
+	}
+	return 0, false
+}
+
+// parseOperand parses query or literal, JSON tokenizer is used for numbers, true, false and null
+func (jp *jsonPathParser) parseOperand() (filterOperand, error) {
+	switch c := jp.peek(); c {
+	case '@', '$':
+		jp.pos++
+		segments, err := jp.parseSegments()
+		return filterOperand{query: &filterQuery{relative: c == '@', segments: segments}}, err
+	case '\'', '"':
+		str, err := jp.parseString()
+		if err != nil {
+			return filterOperand{}, err
+		}
+		return filterOperand{literal: Match{Type: String, Value: appendQuote(nil, str)}}, nil
+	}
+	lxm, err := NewTokenizer([]byte(jp.expr[jp.pos:])).next()
+	if err != nil || !(isNumber(lxm.Type) || lxm.Type == Bool || lxm.Type == Null) {
+		return filterOperand{}, jp.error()
+	}
+	jp.pos += len(lxm.Value)
+	return filterOperand{literal: Match{Type: lxm.Type, Value: lxm.Value}}, nil
+}
+
+func isAlpha(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
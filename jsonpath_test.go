@@ -0,0 +1,142 @@
+package jajson_test
+
+import (
+	"testing"
+
+	"github.com/aleksandrzhukovskii/jajson"
+	"github.com/stretchr/testify/suite"
+)
+
+type JSONPathSuite struct {
+	suite.Suite
+}
+
+func TestJSONPath(t *testing.T) {
+	suite.Run(t, new(JSONPathSuite))
+}
+
+var store = []byte(`{ "store": {
+    "book": [
+      { "category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95 },
+      { "category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99 },
+      { "category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99 },
+      { "category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99 }
+    ],
+    "bicycle": { "color": "red", "price": 399 }
+  }
+}`)
+
+func (t *JSONPathSuite) query(data []byte, expr string) []string {
+	matches, err := jajson.Query(data, expr)
+	t.Require().NoError(err, expr)
+	ret := make([]string, 0, len(matches))
+	for _, match := range matches {
+		ret = append(ret, string(match.Value))
+	}
+	return ret
+}
+
+func (t *JSONPathSuite) TestQueryStore() {
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{expr: `$.store.book[*].author`, expected: []string{`"Nigel Rees"`, `"Evelyn Waugh"`, `"Herman Melville"`, `"J. R. R. Tolkien"`}},
+		{expr: `$..author`, expected: []string{`"Nigel Rees"`, `"Evelyn Waugh"`, `"Herman Melville"`, `"J. R. R. Tolkien"`}},
+		{expr: `$.store..price`, expected: []string{`8.95`, `12.99`, `8.99`, `22.99`, `399`}},
+		{expr: `$..book[2].title`, expected: []string{`"Moby Dick"`}},
+		{expr: `$..book[-1].title`, expected: []string{`"The Lord of the Rings"`}},
+		{expr: `$..book[0,1].price`, expected: []string{`8.95`, `12.99`}},
+		{expr: `$..book[:2].price`, expected: []string{`8.95`, `12.99`}},
+		{expr: `$..book[?@.isbn].price`, expected: []string{`8.99`, `22.99`}},
+		{expr: `$..book[?@.price<10].title`, expected: []string{`"Sayings of the Century"`, `"Moby Dick"`}},
+		{expr: `$..book[?@.price < 10 && @.category == 'fiction'].title`, expected: []string{`"Moby Dick"`}},
+		{expr: `$..book[?!(@.price < 10) || @.author == "Nigel Rees"].price`, expected: []string{`8.95`, `12.99`, `22.99`}},
+		{expr: `$.store.book[?@.price > $.store.book[0].price].price`, expected: []string{`12.99`, `8.99`, `22.99`}},
+		{expr: `$.store['bicycle']["color"]`, expected: []string{`"red"`}},
+		{expr: `$.missing`, expected: []string{}},
+		{expr: `$`, expected: []string{string(store)}},
+	}
+	for _, test := range tests {
+		t.Equal(test.expected, t.query(store, test.expr), test.expr)
+	}
+}
+
+func (t *JSONPathSuite) TestQuerySlices() {
+	data := []byte(`["a", "b", "c", "d", "e", "f", "g"]`)
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{expr: `$[1:3]`, expected: []string{`"b"`, `"c"`}},
+		{expr: `$[5:]`, expected: []string{`"f"`, `"g"`}},
+		{expr: `$[1:5:2]`, expected: []string{`"b"`, `"d"`}},
+		{expr: `$[5:1:-2]`, expected: []string{`"f"`, `"d"`}},
+		{expr: `$[::-1]`, expected: []string{`"g"`, `"f"`, `"e"`, `"d"`, `"c"`, `"b"`, `"a"`}},
+		{expr: `$[-2:]`, expected: []string{`"f"`, `"g"`}},
+		{expr: `$[0:7:0]`, expected: []string{}},
+		{expr: `$[0, 0, -1:]`, expected: []string{`"a"`, `"a"`, `"g"`}},
+		{expr: `$[7]`, expected: []string{}},
+	}
+	for _, test := range tests {
+		t.Equal(test.expected, t.query(data, test.expr), test.expr)
+	}
+}
+
+func (t *JSONPathSuite) TestQueryFilters() {
+	data := []byte(`{"items": [{"price": 1, "qty": 2}, {"price": 2, "qty": 0}, {"price": 3, "qty": 5, "tags": ["a"]}, {"price": 4}],
+		"obj": {"x": {"y": null}, "z": {"y": [1, {"k": 1, "m": "\u0041"}]}}, "other": [1.0, {"m": "A", "k": 1e0}]}`)
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{expr: `$.items[?@.qty > 0].price`, expected: []string{`1`, `3`}},
+		{expr: `$.items[?@.qty >= 0 && @.qty <= 2].price`, expected: []string{`1`, `2`}},
+		{expr: `$.items[?@.qty != 0].price`, expected: []string{`1`, `3`, `4`}},
+		{expr: `$.items[?!@.qty].price`, expected: []string{`4`}},
+		{expr: `$.items[?@.tags == $.items[2].tags].price`, expected: []string{`3`}},
+		{expr: `$.obj[?@.y == null]`, expected: []string{`{"y": null}`}},
+		{expr: `$.obj[?@.y[1].k == 1]`, expected: []string{`{"y": [1, {"k": 1, "m": "\u0041"}]}`}},
+		{expr: `$.obj[?@.y == $.other]`, expected: []string{`{"y": [1, {"k": 1, "m": "\u0041"}]}`}},
+		{expr: `$.items[?@.price == 2.0e0].qty`, expected: []string{`0`}},
+		{expr: `$..[?@.k].k`, expected: []string{`1`, `1e0`}},
+	}
+	for _, test := range tests {
+		t.Equal(test.expected, t.query(data, test.expr), test.expr)
+	}
+
+	matches, err := jajson.Query(data, `$.items[?@.qty > 0]`)
+	t.NoError(err)
+	t.Len(matches, 2)
+	t.Equal(jajson.Object, matches[0].Type)
+}
+
+func (t *JSONPathSuite) TestCompileErrors() {
+	for _, expr := range []string{``, `store`, `$.`, `$[`, `$[01]`, `$[-0]`, `$['a'`, `$[?@.a ==]`, `$[?@.* == 1]`,
+		`$[?@..a == 1]`, `$[?1]`, `$[?length(@) > 1]`, `$[?@.a == [1]]`, `$.a b`, `$[9007199254740992]`, `$["\'"]`} {
+		_, err := jajson.CompileJSONPath(expr)
+		t.ErrorContains(err, "invalid JSONPath expression", expr)
+	}
+
+	path, err := jajson.CompileJSONPath(`$.store.bicycle.color`)
+	t.NoError(err)
+	t.Equal(`$.store.bicycle.color`, path.String())
+	matches, err := jajson.QueryJSONPath(store, path)
+	t.NoError(err)
+	t.Equal([]jajson.Match{{Type: jajson.String, Value: []byte(`"red"`)}}, matches)
+}
+
+func (t *JSONPathSuite) TestQueryFilterNumbers() {
+	data := []byte(`[{"a": 1e400}, {"a": 1}, {"a": -1e400}]`)
+	t.Equal([]string{`{"a": 1}`, `{"a": -1e400}`}, t.query(data, `$[?@.a < 5]`))
+	t.Equal([]string{`{"a": 1e400}`}, t.query(data, `$[?@.a == 2e400]`))
+	t.Equal([]string{`{"a": 1e400}`, `{"a": 1}`}, t.query(data, `$[?@.a > -1e400]`))
+
+	p := jajson.Parser{JSON5: true}
+	for _, expr := range []string{`$[?@ < 20]`, `$[?@ == 16]`, `$[?@ > 15 && @ <= 16]`} {
+		matches, err := p.Query([]byte(`[0x10, +Infinity]`), expr)
+		t.Require().NoError(err, expr)
+		t.Require().NotEmpty(matches, expr)
+		t.Equal(`0x10`, string(matches[0].Value), expr)
+	}
+}
@@ -1,15 +1,18 @@
 package jajson
 
+import "strconv"
+
+// LexemeType is the kind of Token or the type of value
 type LexemeType uint8
 
 const (
 	nothing LexemeType = iota
-	openCurve
-	closeCurve
-	openBracket
-	closeBracket
-	colon
-	comma
+	OpenCurve
+	CloseCurve
+	OpenBracket
+	CloseBracket
+	Colon
+	Comma
 	String
 	Int
 	Float
@@ -18,11 +21,29 @@ const (
 	Object
 	Array
 	Err
+
+	Null
+
+	// Identifier is unquoted JSON5 object key
+	Identifier
 )
 
-type lexeme struct {
-	typ     LexemeType
-	value   []byte
-	pos     int
-	bytePos int
+// Token is a lexeme of JSON, Value is part of the tokenized data
+type Token struct {
+	Type  LexemeType
+	Value []byte
+	// Pos is the offset of the token in runes
+	Pos int
+	// BytePos is the offset of the token in bytes
+	BytePos int
+}
+
+var lexemeNames = [...]string{"nothing", "{", "}", "[", "]", ":", ",", "String", "Int", "Float", "Bool", "Object", "Array", "Err", "Null", "Identifier"}
+
+// String returns the punctuation of the kind or its name
+func (t LexemeType) String() string {
+	if int(t) < len(lexemeNames) {
+		return lexemeNames[t]
+	}
+	return "LexemeType(" + strconv.Itoa(int(t)) + ")"
 }
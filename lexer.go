@@ -1,91 +1,286 @@
 package jajson
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"unicode"
 	"unicode/utf8"
 )
 
 var rue = []rune("rue")
 var alse = []rune("alse")
-var runeToType = map[rune]LexemeType{'{': openCurve, '}': closeCurve, '[': openBracket, ']': closeBracket, ':': colon, ',': comma}
+var ull = []rune("ull")
+var nfinity = []rune("nfinity")
+var aN = []rune("aN")
+var runeToType = map[rune]LexemeType{'{': OpenCurve, '}': CloseCurve, '[': OpenBracket, ']': CloseBracket, ':': Colon, ',': Comma}
 
-type lexer struct {
+// Tokenizer splits JSON into tokens, it is the lexer used by all functions of the package
+type Tokenizer struct {
+	// src is the whole lexed data, it is used to describe errors
+	src     []byte
 	data    []byte
 	pos     int
 	bytePos int
+	// goEscapes makes strings follow Go string literal rules instead of JSON ones
+	goEscapes bool
+	// strictSurrogates makes lone UTF-16 surrogates in keys an error instead of U+FFFD
+	strictSurrogates bool
+	// json5 enables JSON5 strings, numbers, identifier keys and whitespace
+	json5 bool
+	// comments makes // and /* */ comments whitespace
+	comments bool
+	// trailingCommas allows comma after the last member of object or element of array
+	trailingCommas bool
 
-	lookupLexeme lexeme
-	lookupBefore []byte
-	lookupError  error
+	peeked    bool
+	peekToken Token
+	peekError error
+	// end is set when the last token was not found because only whitespace is left
+	end bool
+
+	// reader is the source of data for streaming tokenizer, then src is the window of data read so far
+	reader io.Reader
+	// offset is the byte offset of src in the whole data
+	offset int
+	// keep is the byte offset from which data must stay in the window, it is -1 when only the current token is needed
+	keep int
+	eof  bool
+	// lines and column are the amount of lines and runes of the last line which are discarded from the window
+	lines  int
+	column int
 }
 
-func newLexer(data []byte) *lexer {
-	return &lexer{
+// NewTokenizer returns tokenizer of strict JSON, Parser.NewTokenizer applies options of parser
+func NewTokenizer(data []byte) *Tokenizer {
+	return &Tokenizer{
+		src:     data,
 		data:    data,
 		pos:     0,
 		bytePos: 0,
+		keep:    -1,
 	}
 }
 
-func (t *lexer) lookup() (lexeme, []byte, error) {
-	if t.lookupBefore != nil {
-		return t.lookupLexeme, t.lookupBefore, t.lookupError
+// error returns e at the beginning of the current token
+func (t *Tokenizer) error(e Error) Error {
+	return t.at(e, t.pos, t.bytePos)
+}
+
+// errorAt returns e at the beginning of rest, which is the unread part of the current token
+func (t *Tokenizer) errorAt(e Error, rest []byte) Error {
+	i := len(t.src) - len(rest)
+	return t.at(e, t.pos+utf8.RuneCount(t.src[t.bytePos-t.offset:i]), t.offset+i)
+}
+
+// errorHere returns e at the first unread byte
+func (t *Tokenizer) errorHere(e Error) Error {
+	return t.errorAt(e, t.data)
+}
+
+func (t *Tokenizer) lexemeError(e Error, lxm Token) Error {
+	return t.at(e, lxm.Pos, lxm.BytePos)
+}
+
+// tokenError positions err of decoding lxm at lxm, errors which are already positioned are kept
+func (t *Tokenizer) tokenError(err error, lxm Token) error {
+	var e Error
+	if errors.As(err, &e) && e.src == nil {
+		return t.lexemeError(e, lxm)
 	}
-	t.lookupLexeme, t.lookupBefore, t.lookupError = t.nextToken()
-	return t.lookupLexeme, t.lookupBefore, t.lookupError
+	return err
 }
 
-func (t *lexer) nextToken() (lexeme, []byte, error) {
-	if t.lookupBefore != nil {
-		r1, r2, r3 := t.lookupLexeme, t.lookupBefore, t.lookupError
-		t.lookupLexeme, t.lookupBefore, t.lookupError = lexeme{}, nil, nil
-		return r1, r2, r3
+// at returns e at rune offset pos and byte offset bytePos of the whole data
+func (t *Tokenizer) at(e Error, pos, bytePos int) Error {
+	if t.reader == nil {
+		return e.at(t.src, pos, bytePos)
+	} else if bytePos < t.offset {
+		e.pos = pos
+		e.bytePos = bytePos
+		return e
 	}
-	if len(t.data) == 0 {
-		return lexeme{}, nil, ErrorUnexpected.New(t.pos)
+	// the window is overwritten by the following reads, so the error keeps its copy
+	e = e.at(append([]byte(nil), t.src...), pos, bytePos-t.offset)
+	e.bytePos = bytePos
+	e.src.lines = t.lines
+	e.src.column = t.column
+	return e
+}
+
+// slice returns data between byte offsets, it is nil when the data is already discarded from the window
+func (t *Tokenizer) slice(start, end int) []byte {
+	if start < t.offset {
+		return nil
+	}
+	return t.src[start-t.offset : end-t.offset]
+}
+
+// Next returns the next token, io.EOF is returned when only whitespace is left
+func (t *Tokenizer) Next() (Token, error) {
+	tok, err := t.next()
+	if err != nil && t.end {
+		return Token{}, io.EOF
 	}
-	r, size := utf8.DecodeRune(t.data)
-	if r == utf8.RuneError {
-		return lexeme{}, nil, ErrorRune.New(t.pos)
+	return tok, err
+}
+
+// Peek returns the next token without consuming it, io.EOF is returned when only whitespace is left
+func (t *Tokenizer) Peek() (Token, error) {
+	tok, err := t.peek()
+	if err != nil && t.end {
+		return Token{}, io.EOF
+	}
+	return tok, err
+}
+
+func (t *Tokenizer) peek() (Token, error) {
+	if !t.peeked {
+		t.peekToken, t.peekError = t.next()
+		t.peeked = true
 	}
-	before := t.data
-	t.data = t.data[size:]
-	for unicode.IsSpace(r) && len(t.data) > 0 {
+	return t.peekToken, t.peekError
+}
+
+func (t *Tokenizer) next() (Token, error) {
+	if t.peeked {
+		t.peeked = false
+		return t.peekToken, t.peekError
+	}
+	if t.reader != nil {
+		return t.nextTokenFromReader()
+	}
+	return t.scanToken()
+}
+
+// scanToken lexes the token at the beginning of unread data
+func (t *Tokenizer) scanToken() (Token, error) {
+	if len(t.data) == 0 {
+		t.end = true
+		return Token{}, t.error(ErrorUnexpected)
+	}
+	t.end = false
+	for {
+		r, size := utf8.DecodeRune(t.data)
+		if r == utf8.RuneError {
+			return Token{}, t.error(ErrorRune)
+		}
+		if t.isComment() {
+			if err := t.skipComment(); err != nil {
+				return Token{}, err
+			}
+			if len(t.data) == 0 {
+				t.end = true
+				return Token{}, t.error(ErrorUnexpected)
+			}
+			continue
+		}
+		before := t.data
+		t.data = t.data[size:]
+		if !t.isSpace(r) || len(t.data) == 0 {
+			t.end = t.isSpace(r)
+			if t.json5 {
+				return t.tokenSwitchJSON5(r, before, size)
+			}
+			return t.tokenSwitch(r, before, size)
+		}
 		t.pos++
 		t.bytePos += size
-		r, size = utf8.DecodeRune(t.data)
+	}
+}
+
+// isSpace reports whether r is whitespace, JSON allows only space, tab, line feed and carriage return
+func (t *Tokenizer) isSpace(r rune) bool {
+	if t.json5 {
+		return unicode.IsSpace(r) || r == '\uFEFF'
+	}
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+// isComment reports whether comment starts at the unread data
+func (t *Tokenizer) isComment() bool {
+	return t.comments && len(t.data) > 1 && t.data[0] == '/' && (t.data[1] == '/' || t.data[1] == '*')
+}
+
+// skipComment skips the comment at the unread data, line comment ends before line terminator
+func (t *Tokenizer) skipComment() error {
+	var n int
+	if t.data[1] == '/' {
+		n = bytes.IndexAny(t.data, "\n\r\u2028\u2029")
+		if n < 0 {
+			n = len(t.data)
+		}
+	} else {
+		n = bytes.Index(t.data[2:], []byte("*/"))
+		if n < 0 {
+			return t.error(ErrorUnexpected)
+		}
+		n += 4
+	}
+	if !utf8.Valid(t.data[:n]) {
+		return t.error(ErrorRune)
+	}
+	t.pos += utf8.RuneCount(t.data[:n])
+	t.bytePos += n
+	t.data = t.data[n:]
+	return nil
+}
+
+// skipTrailing skips whitespace after the value and returns error if anything else is left
+func (t *Tokenizer) skipTrailing() error {
+	if t.peeked {
+		return t.lexemeError(ErrorTrailingData, t.peekToken)
+	}
+	for len(t.data) > 0 {
+		if t.isComment() {
+			if err := t.skipComment(); err != nil {
+				return err
+			}
+			continue
+		}
+		r, size := utf8.DecodeRune(t.data)
 		if r == utf8.RuneError {
-			return lexeme{}, nil, ErrorRune.New(t.pos)
+			return t.error(ErrorRune)
+		} else if !t.isSpace(r) {
+			return t.error(ErrorTrailingData)
 		}
-		before = t.data
 		t.data = t.data[size:]
+		t.pos++
+		t.bytePos += size
 	}
-	return t.tokenSwitch(r, before, size)
+	return nil
 }
 
-func (t *lexer) tokenSwitch(r rune, before []byte, size int) (lexeme, []byte, error) {
+func (t *Tokenizer) tokenSwitch(r rune, before []byte, size int) (Token, error) {
 	switch r {
 	case '{', '}', '[', ']', ':', ',':
 		defer func() { t.pos++; t.bytePos += size }()
-		return lexeme{typ: runeToType[r], pos: t.pos, bytePos: t.bytePos}, before, nil
+		return Token{Type: runeToType[r], Pos: t.pos, Value: before[:size], BytePos: t.bytePos}, nil
 	case 't':
 		if err := t.skipRunes(rue); err != nil {
-			return lexeme{}, nil, err
+			return Token{}, err
 		}
 		byteLen := len(before) - len(t.data)
 		defer func() { t.pos += 4; t.bytePos += byteLen }()
-		return lexeme{typ: Bool, pos: t.pos, value: before[:byteLen], bytePos: t.bytePos}, before, nil
+		return Token{Type: Bool, Pos: t.pos, Value: before[:byteLen], BytePos: t.bytePos}, nil
 	case 'f':
 		if err := t.skipRunes(alse); err != nil {
-			return lexeme{}, nil, err
+			return Token{}, err
 		}
 		byteLen := len(before) - len(t.data)
 		defer func() { t.pos += 5; t.bytePos += byteLen }()
-		return lexeme{typ: Bool, pos: t.pos, value: before[:byteLen], bytePos: t.bytePos}, before, nil
+		return Token{Type: Bool, Pos: t.pos, Value: before[:byteLen], BytePos: t.bytePos}, nil
+	case 'n':
+		if err := t.skipRunes(ull); err != nil {
+			return Token{}, err
+		}
+		byteLen := len(before) - len(t.data)
+		defer func() { t.pos += 4; t.bytePos += byteLen }()
+		return Token{Type: Null, Pos: t.pos, Value: before[:byteLen], BytePos: t.bytePos}, nil
 	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-		ret, float, err := t.skipNum(r == '-')
+		ret, float, err := t.skipNum(byte(r))
 		if err != nil {
-			return lexeme{}, nil, err
+			return Token{}, err
 		}
 		byteLen := len(before) - len(t.data)
 		defer func() { t.pos += ret + 1; t.bytePos += byteLen }()
@@ -95,93 +290,237 @@ func (t *lexer) tokenSwitch(r rune, before []byte, size int) (lexeme, []byte, er
 		} else {
 			typ = Int
 		}
-		return lexeme{typ: typ, pos: t.pos, value: before[:byteLen], bytePos: t.bytePos}, before, nil
+		return Token{Type: typ, Pos: t.pos, Value: before[:byteLen], BytePos: t.bytePos}, nil
 	case '"':
-		ret, err := t.skipString()
+		ret, err := t.skipString('"')
 		if err != nil {
-			return lexeme{}, nil, err
+			return Token{}, err
 		}
 		byteLen := len(before) - len(t.data)
 		defer func() { t.pos += ret + 2; t.bytePos += byteLen }()
-		return lexeme{typ: String, pos: t.pos, value: before[:byteLen], bytePos: t.bytePos}, before, nil
+		return Token{Type: String, Pos: t.pos, Value: before[:byteLen], BytePos: t.bytePos}, nil
 	default:
-		return lexeme{}, nil, ErrorUnexpected.New(t.pos)
+		return Token{}, t.error(ErrorUnexpected)
+	}
+}
+
+// tokenSwitchJSON5 lexes JSON5 single quoted strings, numbers and identifiers, the rest is lexed as JSON
+func (t *Tokenizer) tokenSwitchJSON5(r rune, before []byte, size int) (Token, error) {
+	switch {
+	case r == '\'':
+		ret, err := t.skipString('\'')
+		if err != nil {
+			return Token{}, err
+		}
+		return t.valueLexeme(String, before, ret+2), nil
+	case r == '+' || r == '-' || r == '.' || (r < utf8.RuneSelf && isDigit(byte(r))):
+		ret, float, err := t.skipNumJSON5(byte(r))
+		if err != nil {
+			return Token{}, err
+		}
+		typ := Int
+		if float {
+			typ = Float
+		}
+		return t.valueLexeme(typ, before, ret+1), nil
+	case isIdentifierStart(r):
+		ret, err := t.skipIdentifier()
+		if err != nil {
+			return Token{}, err
+		}
+		typ := Identifier
+		switch string(before[:len(before)-len(t.data)]) {
+		case "true", "false":
+			typ = Bool
+		case "null":
+			typ = Null
+		case "Infinity", "NaN":
+			typ = Float
+		}
+		return t.valueLexeme(typ, before, ret+1), nil
+	}
+	return t.tokenSwitch(r, before, size)
+}
+
+// valueLexeme returns token from before up to the unread data and moves position by runes
+func (t *Tokenizer) valueLexeme(typ LexemeType, before []byte, runes int) Token {
+	byteLen := len(before) - len(t.data)
+	lxm := Token{Type: typ, Pos: t.pos, Value: before[:byteLen], BytePos: t.bytePos}
+	t.pos += runes
+	t.bytePos += byteLen
+	return lxm
+}
+
+// skipIdentifier skips the rest of ECMAScript identifier name, escapes in identifiers are not supported
+func (t *Tokenizer) skipIdentifier() (int, error) {
+	ret := 0
+	for len(t.data) > 0 {
+		r, size := utf8.DecodeRune(t.data)
+		if r == utf8.RuneError {
+			return 0, t.errorHere(ErrorRune)
+		} else if !isIdentifierPart(r) {
+			break
+		}
+		t.data = t.data[size:]
+		ret++
 	}
+	return ret, nil
+}
+
+func isIdentifierStart(r rune) bool {
+	return r == '$' || r == '_' || unicode.IsLetter(r) || unicode.Is(unicode.Nl, r)
+}
+
+func isIdentifierPart(r rune) bool {
+	return isIdentifierStart(r) || unicode.In(r, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc) ||
+		r == '\u200C' || r == '\u200D'
 }
 
-func (t *lexer) skipRunes(str []rune) error {
+func (t *Tokenizer) skipRunes(str []rune) error {
 	for i := 0; i < len(str); i++ {
 		if len(t.data) == 0 {
-			return ErrorUnexpected.New(t.pos)
+			return t.errorHere(ErrorUnexpected)
 		}
 		r, size := utf8.DecodeRune(t.data)
 		if r == utf8.RuneError {
-			return ErrorRune.New(t.pos)
+			return t.errorHere(ErrorRune)
+		} else if r != str[i] {
+			return t.errorHere(ErrorUnexpected)
 		}
 		t.data = t.data[size:]
-		if r != str[i] {
-			return ErrorUnexpected.New(t.pos)
-		}
 	}
 	return nil
 }
 
-func (t *lexer) skipNum(zeroCritical bool) (int, bool, error) {
+// skipNum skips the rest of the number after its first rune according to RFC 8259:
+// -?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?
+// it returns the amount of skipped runes and whether the number has fraction or exponent
+func (t *Tokenizer) skipNum(first byte) (int, bool, error) {
 	ret := 0
-	float := false
-	point := false
-	for len(t.data) != 0 {
-		size, r, err := t.skipNumDecodeRune(zeroCritical, point, ret)
-		if r == utf8.RuneError {
-			if err == nil {
-				return ret, float, nil
-			}
-			return 0, false, err
+	if first == '-' {
+		if len(t.data) == 0 || !isDigit(t.data[0]) {
+			return 0, false, t.errorHere(ErrorUnexpected)
 		}
-		point = false
-		if r == '.' {
-			point = true
-			if float {
-				return 0, false, ErrorUnexpected.New(t.pos)
-			}
-			float = true
+		first = t.data[0]
+		t.data = t.data[1:]
+		ret++
+	}
+	if first == '0' {
+		if len(t.data) > 0 && isDigit(t.data[0]) {
+			return 0, false, t.errorHere(ErrorUnexpected)
 		}
-		t.data = t.data[size:]
+	} else {
+		ret += t.skipDigits()
+	}
+
+	float := false
+	if len(t.data) > 0 && t.data[0] == '.' {
+		float = true
+		t.data = t.data[1:]
+		n := t.skipDigits()
+		if n == 0 {
+			return 0, false, t.errorHere(ErrorUnexpected)
+		}
+		ret += n + 1
+	}
+	n, exp, err := t.skipExponent()
+	return ret + n, float || exp, err
+}
+
+// skipExponent skips the exponent part of the number if it is present
+func (t *Tokenizer) skipExponent() (int, bool, error) {
+	if len(t.data) == 0 || (t.data[0] != 'e' && t.data[0] != 'E') {
+		return 0, false, nil
+	}
+	t.data = t.data[1:]
+	ret := 1
+	if len(t.data) > 0 && (t.data[0] == '+' || t.data[0] == '-') {
+		t.data = t.data[1:]
 		ret++
 	}
-	if ret == 0 && zeroCritical {
-		return 0, false, ErrorUnexpected.New(t.pos)
+	n := t.skipDigits()
+	if n == 0 {
+		return 0, false, t.errorHere(ErrorUnexpected)
 	}
-	return ret, float, nil
+	return ret + n, true, nil
 }
 
-func (t *lexer) skipNumDecodeRune(zeroCritical, point bool, ret int) (int, rune, error) {
-	r, size := utf8.DecodeRune(t.data)
-	if r == utf8.RuneError {
-		if ret == 0 && zeroCritical {
-			return 0, utf8.RuneError, ErrorRune.New(t.pos)
-		} else if point {
-			return 0, utf8.RuneError, ErrorUnexpected.New(t.pos)
+// skipNumJSON5 skips the rest of JSON5 number after its first rune, in addition to JSON it allows
+// leading + sign, hexadecimal integers, Infinity, NaN and leading or trailing decimal point
+func (t *Tokenizer) skipNumJSON5(first byte) (int, bool, error) {
+	ret := 0
+	if first == '+' || first == '-' {
+		if len(t.data) == 0 || !(isDigit(t.data[0]) || t.data[0] == '.' || t.data[0] == 'I' || t.data[0] == 'N') {
+			return 0, false, t.errorHere(ErrorUnexpected)
 		}
-		return 0, utf8.RuneError, nil
+		first = t.data[0]
+		t.data = t.data[1:]
+		ret++
 	}
-	if r != '.' && !(r >= '0' && r <= '9') {
-		if point {
-			return 0, utf8.RuneError, ErrorUnexpected.New(t.pos)
-		} else if ret == 0 && zeroCritical {
-			return 0, utf8.RuneError, ErrorUnexpected.New(t.pos)
+	switch {
+	case first == 'I':
+		return ret + len(nfinity), true, t.skipRunes(nfinity)
+	case first == 'N':
+		return ret + len(aN), true, t.skipRunes(aN)
+	case first == '0' && len(t.data) > 0 && (t.data[0] == 'x' || t.data[0] == 'X'):
+		t.data = t.data[1:]
+		n := 0
+		for n < len(t.data) {
+			if _, ok := unhex(t.data[n]); !ok {
+				break
+			}
+			n++
 		}
-		return 0, utf8.RuneError, nil
+		if n == 0 {
+			return 0, false, t.errorHere(ErrorUnexpected)
+		}
+		t.data = t.data[n:]
+		return ret + n + 1, false, nil
+	case first == '0':
+		if len(t.data) > 0 && isDigit(t.data[0]) {
+			return 0, false, t.errorHere(ErrorUnexpected)
+		}
+	case first == '.':
+		n := t.skipDigits()
+		if n == 0 {
+			return 0, false, t.errorHere(ErrorUnexpected)
+		}
+		n2, _, err := t.skipExponent()
+		return ret + n + n2, true, err
+	default:
+		ret += t.skipDigits()
+	}
+
+	float := false
+	if len(t.data) > 0 && t.data[0] == '.' {
+		float = true
+		t.data = t.data[1:]
+		ret += t.skipDigits() + 1
+	}
+	n, exp, err := t.skipExponent()
+	return ret + n, float || exp, err
+}
+
+func (t *Tokenizer) skipDigits() int {
+	i := 0
+	for i < len(t.data) && isDigit(t.data[i]) {
+		i++
 	}
-	return size, r, nil
+	t.data = t.data[i:]
+	return i
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
 }
 
-func (t *lexer) skipString() (int, error) {
+// skipString skips the rest of the string after its opening quote
+func (t *Tokenizer) skipString(quote byte) (int, error) {
 	if len(t.data) < 1 {
-		return 0, ErrorUnexpected.New(t.pos)
+		return 0, t.errorHere(ErrorUnexpected)
 	}
 	ret := 0
-	for len(t.data) > 0 && t.data[0] != '"' {
+	for len(t.data) > 0 && t.data[0] != quote {
 		l, err := t.skipChar()
 		if err != nil {
 			return 0, err
@@ -190,21 +529,23 @@ func (t *lexer) skipString() (int, error) {
 	}
 
 	if len(t.data) == 0 {
-		return 0, ErrorUnexpected.New(t.pos)
+		return 0, t.errorHere(ErrorUnexpected)
 	}
 	t.data = t.data[1:]
 	return ret, nil
 }
 
-func (t *lexer) skipChar() (int, error) {
+func (t *Tokenizer) skipChar() (int, error) {
 	switch c := t.data[0]; {
 	case c >= utf8.RuneSelf:
 		r, size := utf8.DecodeRune(t.data)
 		if r == utf8.RuneError {
-			return 0, ErrorRune.New(t.pos)
+			return 0, t.errorHere(ErrorRune)
 		}
 		t.data = t.data[size:]
 		return 1, nil
+	case c < 0x20 && !t.goEscapes && (!t.json5 || c == '\n' || c == '\r'):
+		return 0, t.errorHere(ErrorUnexpected)
 	case c != '\\':
 		t.data = t.data[1:]
 		return 1, nil
@@ -212,33 +553,82 @@ func (t *lexer) skipChar() (int, error) {
 
 	// hard case: c is backslash
 	if len(t.data) < 2 {
-		return 0, ErrorUnexpected.New(t.pos)
+		return 0, t.errorHere(ErrorUnexpected)
 	}
+	esc := t.data
 	c := t.data[1]
 	t.data = t.data[2:]
+	if t.goEscapes {
+		return t.skipGoEscape(c, esc)
+	} else if t.json5 {
+		return t.skipJSON5Escape(c, esc)
+	}
 
+	switch c {
+	case 'b', 'f', 'n', 'r', 't', '/', '\\', '"':
+		return 2, nil
+	case 'u':
+		return t.skipNumHex(c, esc)
+	case '\'':
+		return 0, t.errorAt(ErrorWrongQuote, esc)
+	default:
+		return 0, t.errorAt(ErrorUnexpected, esc)
+	}
+}
+
+func (t *Tokenizer) skipGoEscape(c byte, esc []byte) (int, error) {
 	switch c {
 	case 'a', 'b', 'f', 'n', 'r', 't', 'v', '\\', '"':
 		return 2, nil
 	case 'x', 'u', 'U':
-		n, err := t.skipNumHex(c)
+		n, err := t.skipNumHex(c, esc)
 		if err != nil {
 			return 0, err
 		}
 		return n, nil
 	case '0', '1', '2', '3', '4', '5', '6', '7':
-		if err := t.skipNumOct(rune(c) - '0'); err != nil {
+		if err := t.skipNumOct(rune(c)-'0', esc); err != nil {
 			return 0, err
 		}
 		return 4, nil
 	case '\'':
-		return 0, ErrorWrongQuote.New(t.pos)
+		return 0, t.errorAt(ErrorWrongQuote, esc)
 	default:
-		return 0, ErrorUnexpected.New(t.pos)
+		return 0, t.errorAt(ErrorUnexpected, esc)
+	}
+}
+
+// skipJSON5Escape skips JSON5 escape sequence, any character except digits and x, u escapes itself
+// and escaped line terminator continues the string on the next line
+func (t *Tokenizer) skipJSON5Escape(c byte, esc []byte) (int, error) {
+	switch {
+	case c == 'x' || c == 'u':
+		return t.skipNumHex(c, esc)
+	case c == '0':
+		if len(t.data) > 0 && isDigit(t.data[0]) {
+			return 0, t.errorAt(ErrorUnexpected, esc)
+		}
+		return 2, nil
+	case isDigit(c):
+		return 0, t.errorAt(ErrorUnexpected, esc)
+	case c == '\r':
+		if len(t.data) > 0 && t.data[0] == '\n' {
+			t.data = t.data[1:]
+			return 3, nil
+		}
+		return 2, nil
+	case c >= utf8.RuneSelf:
+		r, size := utf8.DecodeRune(esc[1:])
+		if r == utf8.RuneError {
+			return 0, t.errorAt(ErrorRune, esc)
+		}
+		t.data = esc[1+size:]
+		return 2, nil
 	}
+	return 2, nil
 }
 
-func (t *lexer) skipNumHex(c byte) (int, error) {
+func (t *Tokenizer) skipNumHex(c byte, esc []byte) (int, error) {
 	n := 0
 	switch c {
 	case 'x':
@@ -250,44 +640,45 @@ func (t *lexer) skipNumHex(c byte) (int, error) {
 	}
 	var v rune
 	if len(t.data) < n {
-		return 0, ErrorUnexpected.New(t.pos)
+		return 0, t.errorAt(ErrorUnexpected, esc)
 	}
 	for j := 0; j < n; j++ {
-		x, ok := t.unhex(t.data[j])
+		x, ok := unhex(t.data[j])
 		if !ok {
-			return 0, ErrorUnexpected.New(t.pos)
+			return 0, t.errorAt(ErrorUnexpected, esc)
 		}
 		v = v<<4 | x
 	}
 	t.data = t.data[n:]
-	if c == 'x' {
+	// JSON allows any code unit in \u escapes including surrogate halves
+	if c == 'x' || !t.goEscapes {
 		return 2 + n, nil
 	}
 	if !utf8.ValidRune(v) {
-		return 0, ErrorRune.New(t.pos)
+		return 0, t.errorAt(ErrorRune, esc)
 	}
 	return n + 2, nil
 }
 
-func (t *lexer) skipNumOct(v rune) error {
+func (t *Tokenizer) skipNumOct(v rune, esc []byte) error {
 	if len(t.data) < 2 {
-		return ErrorUnexpected.New(t.pos)
+		return t.errorAt(ErrorUnexpected, esc)
 	}
 	for j := 0; j < 2; j++ { // one digit already; two more
 		x := rune(t.data[j]) - '0'
 		if x < 0 || x > 7 {
-			return ErrorUnexpected.New(t.pos)
+			return t.errorAt(ErrorUnexpected, esc)
 		}
 		v = (v << 3) | x
 	}
 	t.data = t.data[2:]
 	if v > 255 {
-		return ErrorUnexpected.New(t.pos)
+		return t.errorAt(ErrorUnexpected, esc)
 	}
 	return nil
 }
 
-func (t *lexer) unhex(b byte) (v rune, ok bool) {
+func unhex(b byte) (v rune, ok bool) {
 	c := rune(b)
 	switch {
 	case '0' <= c && c <= '9':
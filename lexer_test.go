@@ -16,17 +16,18 @@ func TestLexer(t *testing.T) {
 
 func (t *LexerSuite) TestNextTokenOK() {
 	testCase := `   [  {  ]  }  true  false 123 12 1 -123 -12 -1 123.5 -123.56  :  "abd" "☺" "\xFF" "\377" "\u1234" "\U00010111" "\U0001011111" "\a\b\f\n\r\t\v\\\"" "\a"  ,  123{  `
-	l := newLexer([]byte(testCase))
+	l := NewTokenizer([]byte(testCase))
+	l.goEscapes = true
 	check := []struct {
 		pos     int
 		bytePos int
 		typ     LexemeType
 		value   []byte
 	}{
-		{pos: 3, bytePos: 3, typ: openBracket},
-		{pos: 6, bytePos: 6, typ: openCurve},
-		{pos: 9, bytePos: 9, typ: closeBracket},
-		{pos: 12, bytePos: 12, typ: closeCurve},
+		{pos: 3, bytePos: 3, typ: OpenBracket},
+		{pos: 6, bytePos: 6, typ: OpenCurve},
+		{pos: 9, bytePos: 9, typ: CloseBracket},
+		{pos: 12, bytePos: 12, typ: CloseCurve},
 		{pos: 15, bytePos: 15, typ: Bool, value: []byte("true")},
 		{pos: 21, bytePos: 21, typ: Bool, value: []byte("false")},
 		{pos: 27, bytePos: 27, typ: Int, value: []byte("123")},
@@ -37,7 +38,7 @@ func (t *LexerSuite) TestNextTokenOK() {
 		{pos: 45, bytePos: 45, typ: Int, value: []byte("-1")},
 		{pos: 48, bytePos: 48, typ: Float, value: []byte("123.5")},
 		{pos: 54, bytePos: 54, typ: Float, value: []byte("-123.56")},
-		{pos: 63, bytePos: 63, typ: colon},
+		{pos: 63, bytePos: 63, typ: Colon},
 		{pos: 66, bytePos: 66, typ: String, value: []byte(`"abd"`)},
 		{pos: 72, bytePos: 72, typ: String, value: []byte(`"☺"`)},
 		{pos: 76, bytePos: 78, typ: String, value: []byte(`"\xFF"`)},
@@ -47,25 +48,148 @@ func (t *LexerSuite) TestNextTokenOK() {
 		{pos: 112, bytePos: 114, typ: String, value: []byte(`"\U0001011111"`)},
 		{pos: 127, bytePos: 129, typ: String, value: []byte(`"\a\b\f\n\r\t\v\\\""`)},
 		{pos: 148, bytePos: 150, typ: String, value: []byte(`"\a"`)},
-		{pos: 154, bytePos: 156, typ: comma},
+		{pos: 154, bytePos: 156, typ: Comma},
 		{pos: 157, bytePos: 159, typ: Int, value: []byte("123")},
-		{pos: 160, bytePos: 162, typ: openCurve},
+		{pos: 160, bytePos: 162, typ: OpenCurve},
 	}
 	for i := 0; i < len(check); i++ {
-		lex, _, err := l.nextToken()
+		lex, err := l.next()
 		t.NoError(err)
-		t.Equal(check[i].typ, lex.typ)
-		t.Equal(check[i].pos, lex.pos)
+		t.Equal(check[i].typ, lex.Type)
+		t.Equal(check[i].pos, lex.Pos)
 		if check[i].value != nil {
-			t.Equal(check[i].value, lex.value)
+			t.Equal(check[i].value, lex.Value)
 		} else {
-			t.Empty(lex.value)
+			// punctuation is the raw byte
+			t.Equal(check[i].typ.String(), string(lex.Value))
 		}
 		if check[i].bytePos != 0 {
-			t.Equal(check[i].bytePos, lex.bytePos)
+			t.Equal(check[i].bytePos, lex.BytePos)
 		}
 	}
-	lex, _, err := l.nextToken()
-	t.Equal(lexeme{}, lex)
-	t.EqualError(err, ErrorUnexpected.New(len([]rune(testCase))-1).Error())
+	lex, err := l.next()
+	t.Equal(Token{}, lex)
+	t.ErrorContains(err, ErrorUnexpected.err.Error())
+	t.Equal(len([]rune(testCase))-1, err.(Error).Pos())
+}
+
+func (t *LexerSuite) TestNextTokenNull() {
+	l := NewTokenizer([]byte(` null,nul`))
+	lex, err := l.next()
+	t.NoError(err)
+	t.Equal(Null, lex.Type)
+	t.Equal(1, lex.Pos)
+	t.Equal([]byte("null"), lex.Value)
+
+	lex, err = l.next()
+	t.NoError(err)
+	t.Equal(Comma, lex.Type)
+
+	_, err = l.next()
+	t.Error(err)
+}
+
+func (t *LexerSuite) TestNextTokenNumber() {
+	valid := []struct {
+		value string
+		typ   LexemeType
+	}{
+		{value: "0", typ: Int},
+		{value: "-0", typ: Int},
+		{value: "10", typ: Int},
+		{value: "0.5", typ: Float},
+		{value: "1e10", typ: Float},
+		{value: "-2.5E-3", typ: Float},
+		{value: "6.02e+23", typ: Float},
+		{value: "0e0", typ: Float},
+	}
+	for _, test := range valid {
+		l := NewTokenizer([]byte(test.value + ","))
+		lex, err := l.next()
+		t.NoError(err, test.value)
+		t.Equal(test.typ, lex.Type, test.value)
+		t.Equal([]byte(test.value), lex.Value)
+		t.Equal(len(test.value), l.pos)
+	}
+
+	invalid := []string{"0123", "-00", "-", "-a", "1.", "1.e5", "1e", "1e+", ".5", "+1", "1E-"}
+	for _, test := range invalid {
+		l := NewTokenizer([]byte(test))
+		_, err := l.next()
+		t.Error(err, test)
+	}
+}
+
+func (t *LexerSuite) TestNextTokenStrictString() {
+	valid := []string{`"abc"`, `"\/"`, `"\"\\\/\b\f\n\r\t"`, `"\u1234\uD83D\uDE00"`, `"☺"`}
+	for _, test := range valid {
+		l := NewTokenizer([]byte(test))
+		lex, err := l.next()
+		t.NoError(err, test)
+		t.Equal(String, lex.Type)
+		t.Equal([]byte(test), lex.Value)
+	}
+
+	invalid := []string{`"\a"`, `"\v"`, `"\x41"`, `"\377"`, `"\U0001F600"`, `"\u12"`, "\"\x01\"", "\"a\nb\"", `"\'"`}
+	for _, test := range invalid {
+		l := NewTokenizer([]byte(test))
+		_, err := l.next()
+		t.Error(err, test)
+	}
+
+	l := NewTokenizer([]byte(`"\/"`))
+	l.goEscapes = true
+	_, err := l.next()
+	t.Error(err)
+}
+
+func (t *LexerSuite) TestErrorPosition() {
+	l := NewTokenizer([]byte("{\n\t\"ключ\": tru }"))
+	for i := 0; i < 3; i++ {
+		_, err := l.next()
+		t.NoError(err)
+	}
+	_, err := l.next()
+	e, ok := err.(Error)
+	t.Require().True(ok)
+	t.Equal(14, e.Pos())
+	t.Equal(18, e.BytePos())
+	t.Equal(2, e.Line())
+	t.Equal(13, e.Column())
+	t.Equal("\t\"ключ\": tru }\n\t           ^", e.Context())
+	t.Equal("Line: 2. Column: 13. Pos: 14. Error: unexpected symbol or end of JSON", e.Error())
+}
+
+func (t *LexerSuite) TestNextTokenJSON5() {
+	l := NewTokenizer([]byte("/* ☺ */ {key: 'a\\'b', // c\n $_x1: -.5e1, true: +0x1F,}"))
+	l.json5 = true
+	l.comments = true
+	check := []struct {
+		pos   int
+		typ   LexemeType
+		value string
+	}{
+		{pos: 8, typ: OpenCurve, value: "{"},
+		{pos: 9, typ: Identifier, value: "key"},
+		{pos: 12, typ: Colon, value: ":"},
+		{pos: 14, typ: String, value: `'a\'b'`},
+		{pos: 20, typ: Comma, value: ","},
+		{pos: 28, typ: Identifier, value: "$_x1"},
+		{pos: 32, typ: Colon, value: ":"},
+		{pos: 34, typ: Float, value: "-.5e1"},
+		{pos: 39, typ: Comma, value: ","},
+		{pos: 41, typ: Bool, value: "true"},
+		{pos: 45, typ: Colon, value: ":"},
+		{pos: 47, typ: Int, value: "+0x1F"},
+		{pos: 52, typ: Comma, value: ","},
+		{pos: 53, typ: CloseCurve, value: "}"},
+	}
+	for _, test := range check {
+		lxm, err := l.next()
+		t.Require().NoError(err)
+		t.Equal(test.typ, lxm.Type)
+		t.Equal(test.pos, lxm.Pos)
+		t.Equal(test.value, string(lxm.Value))
+	}
+	t.NoError(l.skipTrailing())
 }
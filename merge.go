@@ -0,0 +1,184 @@
+package jajson
+
+import (
+	"bytes"
+	"errors"
+)
+
+// MergePatch returns copy of target with RFC 7396 JSON merge patch applied
+func MergePatch(target, patch []byte) ([]byte, error) {
+	return defaultParser.MergePatch(target, patch)
+}
+
+// CreateMergePatch returns RFC 7396 JSON merge patch which turns original into modified
+func CreateMergePatch(original, modified []byte) ([]byte, error) {
+	return defaultParser.CreateMergePatch(original, modified)
+}
+
+// MergePatch returns copy of target with RFC 7396 JSON merge patch applied. Members of patch with null value
+// are removed, the other ones are merged into target in place or added to the end of their object,
+// so the order of members and untouched bytes of target are preserved
+func (p *Parser) MergePatch(target, patch []byte) ([]byte, error) {
+	if err := p.Validate(target); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(patch); err != nil {
+		return nil, err
+	}
+	_, patch, _ = p.GetRawValue(patch)
+	ret, err := p.mergePatch(target, patch)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), ret...), nil
+}
+
+// mergePatch applies valid patch to valid target, target is nil when the member is missing
+func (p *Parser) mergePatch(target, patch []byte) ([]byte, error) {
+	typ, members, err := parseChildren(p.NewTokenizer(patch))
+	if err != nil || typ != Object {
+		return patch, err
+	}
+	if target != nil {
+		if lxm, err := p.NewTokenizer(target).peek(); err != nil {
+			return nil, err
+		} else if lxm.Type != OpenCurve {
+			target = nil
+		}
+	}
+	if target == nil {
+		target = []byte("{}")
+	}
+	for _, member := range members {
+		key, err := p.unescape(member.key, nil)
+		if err != nil {
+			return nil, err
+		}
+		if target, err = p.mergeMember(target, key, member.typ, member.value); err != nil {
+			return nil, err
+		}
+	}
+	return target, nil
+}
+
+// mergeMember applies member of patch to target object
+func (p *Parser) mergeMember(target, key []byte, typ LexemeType, value []byte) ([]byte, error) {
+	lex := p.NewTokenizer(target)
+	if typ == Null {
+		span, err := findChild(lex, string(key), pathIndex)
+		if errors.Is(err, ErrorWrongPath) {
+			return target, nil
+		} else if err != nil {
+			return nil, err
+		}
+		return removeChild(target, span), nil
+	}
+	open, err := openContainer(lex, OpenCurve)
+	if err != nil {
+		return nil, err
+	}
+	found, end, err := findMember(lex, key, open.BytePos+1)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		merged, err := p.mergePatch(nil, value)
+		if err != nil {
+			return nil, err
+		}
+		return addMember(target, location{start: end, empty: end == open.BytePos+1}, key, merged), nil
+	}
+	lxm, err := lex.peek()
+	if err != nil {
+		return nil, err
+	}
+	_, old, err := parseValue(lex)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := p.mergePatch(old, value)
+	if err != nil {
+		return nil, err
+	}
+	return splice(target, lxm.BytePos, lxm.BytePos+len(old), merged), nil
+}
+
+// CreateMergePatch returns RFC 7396 JSON merge patch which turns original into modified. Members are
+// compared by value, the patch contains changed and added members in the order of modified followed
+// by removed ones. Null members of added objects cannot be expressed by merge patch and are kept as is
+func (p *Parser) CreateMergePatch(original, modified []byte) ([]byte, error) {
+	if err := p.Validate(original); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(modified); err != nil {
+		return nil, err
+	}
+	typA, a, _ := p.GetRawValue(original)
+	typB, b, _ := p.GetRawValue(modified)
+	return p.appendMergePatch(nil, typA, a, typB, b)
+}
+
+func (p *Parser) appendMergePatch(buf []byte, typA LexemeType, a []byte, typB LexemeType, b []byte) ([]byte, error) {
+	if typA != Object || typB != Object {
+		return append(buf, b...), nil
+	}
+	_, membersA, err := parseChildren(p.NewTokenizer(a))
+	if err != nil {
+		return nil, err
+	}
+	_, membersB, err := parseChildren(p.NewTokenizer(b))
+	if err != nil {
+		return nil, err
+	}
+	original := make(map[string]child, len(membersA))
+	for _, member := range membersA {
+		key, err := p.unescape(member.key, nil)
+		if err != nil {
+			return nil, err
+		}
+		original[string(key)] = member
+	}
+
+	buf = append(buf, '{')
+	empty := len(buf)
+	modified := make(map[string]bool, len(membersB))
+	for _, member := range membersB {
+		key, err := p.unescape(member.key, nil)
+		if err != nil {
+			return nil, err
+		}
+		modified[string(key)] = true
+		var value []byte
+		if old, ok := original[string(key)]; !ok {
+			value = member.value
+		} else if old.typ == Object && member.typ == Object {
+			if value, err = p.appendMergePatch(nil, old.typ, old.value, member.typ, member.value); err != nil {
+				return nil, err
+			} else if bytes.Equal(value, []byte("{}")) {
+				continue
+			}
+		} else if equal, err := p.equalValues(old.typ, old.value, member.typ, member.value); err != nil {
+			return nil, err
+		} else if !equal {
+			value = member.value
+		} else {
+			continue
+		}
+		if len(buf) > empty {
+			buf = append(buf, ',')
+		}
+		buf = appendMember(buf, key, value)
+	}
+	for _, member := range membersA {
+		key, _ := p.unescape(member.key, nil)
+		if modified[string(key)] {
+			continue
+		}
+		modified[string(key)] = true
+		if len(buf) > empty {
+			buf = append(buf, ',')
+		}
+		buf = appendMember(buf, key, []byte("null"))
+	}
+	return append(buf, '}'), nil
+}
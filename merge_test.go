@@ -0,0 +1,97 @@
+package jajson_test
+
+import (
+	"testing"
+
+	"github.com/aleksandrzhukovskii/jajson"
+	"github.com/stretchr/testify/suite"
+)
+
+type MergeSuite struct {
+	suite.Suite
+}
+
+func TestMerge(t *testing.T) {
+	suite.Run(t, new(MergeSuite))
+}
+
+// rfc7396Examples are the examples of RFC 7396 Appendix A
+var rfc7396Examples = []struct {
+	target, patch, expected string
+}{
+	{target: `{"a":"b"}`, patch: `{"a":"c"}`, expected: `{"a":"c"}`},
+	{target: `{"a":"b"}`, patch: `{"b":"c"}`, expected: `{"a":"b","b":"c"}`},
+	{target: `{"a":"b"}`, patch: `{"a":null}`, expected: `{}`},
+	{target: `{"a":"b","b":"c"}`, patch: `{"a":null}`, expected: `{"b":"c"}`},
+	{target: `{"a":["b"]}`, patch: `{"a":"c"}`, expected: `{"a":"c"}`},
+	{target: `{"a":"c"}`, patch: `{"a":["b"]}`, expected: `{"a":["b"]}`},
+	{target: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, expected: `{"a":{"b":"d"}}`},
+	{target: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, expected: `{"a":[1]}`},
+	{target: `["a","b"]`, patch: `["c","d"]`, expected: `["c","d"]`},
+	{target: `{"a":"b"}`, patch: `["c"]`, expected: `["c"]`},
+	{target: `{"a":"foo"}`, patch: `null`, expected: `null`},
+	{target: `{"a":"foo"}`, patch: `"bar"`, expected: `"bar"`},
+	{target: `{"e":null}`, patch: `{"a":1}`, expected: `{"e":null,"a":1}`},
+	{target: `[1,2]`, patch: `{"a":"b","c":null}`, expected: `{"a":"b"}`},
+	{target: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, expected: `{"a":{"bb":{}}}`},
+}
+
+func (t *MergeSuite) TestMergePatch() {
+	for _, test := range rfc7396Examples {
+		ret, err := jajson.MergePatch([]byte(test.target), []byte(test.patch))
+		t.Require().NoError(err, test.patch)
+		t.Equal(test.expected, string(ret), test.patch)
+	}
+}
+
+func (t *MergeSuite) TestMergePatchPreservesOrder() {
+	target := []byte("{\n  \"title\": \"Goodbye!\",\n  \"author\": {\"givenName\": \"John\", \"familyName\": \"Doe\"},\n  \"tags\": [\"example\", \"sample\"],\n  \"content\": \"This will be unchanged\"\n}")
+	patch := []byte(`{"title": "Hello!", "phoneNumber": "+01-123-456-7890", "author": {"familyName": null}, "tags": ["example"]}`)
+	ret, err := jajson.MergePatch(target, patch)
+	t.Require().NoError(err)
+	t.Equal("{\n  \"title\": \"Hello!\",\n  \"author\": {\"givenName\": \"John\"},\n  \"tags\": [\"example\"],\n  \"content\": \"This will be unchanged\",\"phoneNumber\":\"+01-123-456-7890\"\n}", string(ret))
+
+	ret, err = jajson.MergePatch([]byte(`{"a\"b": 1, "[0]": 2}`), []byte(`{"a\"b": null, "[0]": 3}`))
+	t.Require().NoError(err)
+	t.Equal(`{"[0]": 3}`, string(ret))
+}
+
+func (t *MergeSuite) TestMergePatchErrors() {
+	_, err := jajson.MergePatch([]byte(`{"a": 1`), []byte(`{}`))
+	t.ErrorIs(err, jajson.ErrorUnexpected)
+	_, err = jajson.MergePatch([]byte(`{}`), []byte(`{"a": }`))
+	t.ErrorIs(err, jajson.ErrorUnexpectedLexeme)
+	_, err = jajson.MergePatch(nil, []byte(`{}`))
+	t.ErrorIs(err, jajson.ErrorEmptyJSON)
+}
+
+func (t *MergeSuite) TestCreateMergePatch() {
+	tests := []struct {
+		original, modified, expected string
+	}{
+		{original: `{"a": 1, "b": 2}`, modified: `{"a": 1, "b": 2}`, expected: `{}`},
+		{original: `{"a": 1, "b": 2}`, modified: `{"b": 2.0, "a": 1e0}`, expected: `{}`},
+		{original: `{"a": 1, "b": 2}`, modified: `{"a": 3}`, expected: `{"a":3,"b":null}`},
+		{original: `{"a": {"x": 1, "y": [1]}}`, modified: `{"a": {"x": 1, "y": [1, 2]}, "c": {"d": true}}`, expected: `{"a":{"y":[1, 2]},"c":{"d": true}}`},
+		{original: `{"a": {"x": 1}}`, modified: `{"a": {"x": 1}}`, expected: `{}`},
+		{original: `{"a": "A"}`, modified: `{"a": "A", "b\"": null}`, expected: `{"b\"":null}`},
+		{original: `[1]`, modified: `[1]`, expected: `[1]`},
+		{original: `{"a": 1}`, modified: ` "x" `, expected: `"x"`},
+		{original: `{"id": 9007199254740993}`, modified: `{"id": 9007199254740992}`, expected: `{"id":9007199254740992}`},
+		{original: `{"id": 123456789012345678901234567890}`, modified: `{"id": 123456789012345678901234567890}`, expected: `{}`},
+		{original: `{"id": -0}`, modified: `{"id": 0}`, expected: `{}`},
+	}
+	for _, test := range tests {
+		patch, err := jajson.CreateMergePatch([]byte(test.original), []byte(test.modified))
+		t.Require().NoError(err, test.modified)
+		t.Equal(test.expected, string(patch), test.modified)
+	}
+
+	for _, test := range rfc7396Examples {
+		patch, err := jajson.CreateMergePatch([]byte(test.target), []byte(test.expected))
+		t.Require().NoError(err, test.expected)
+		ret, err := jajson.MergePatch([]byte(test.target), patch)
+		t.Require().NoError(err, test.expected)
+		t.Equal(test.expected, string(ret))
+	}
+}
@@ -0,0 +1,413 @@
+package jajson
+
+import (
+	"bytes"
+	"math"
+)
+
+// Set returns copy of data where the value found by path is replaced with value
+func Set(data, value []byte, path ...string) ([]byte, error) {
+	return defaultParser.Set(data, value, path...)
+}
+
+// Set returns copy of data where the value found by path is replaced with value, untouched bytes are preserved.
+// Missing members are added to the end of their object and missing intermediate objects are created,
+// array elements are not created. Data and value must be valid JSON for the options of parser
+func (p *Parser) Set(data, value []byte, path ...string) ([]byte, error) {
+	if err := p.Validate(data); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(value); err != nil {
+		return nil, err
+	}
+	lex := p.NewTokenizer(data)
+	loc, err := locate(lex, path, pathIndex)
+	if err == nil && loc.missing < 0 {
+		var lxm Token
+		var val []byte
+		if lxm, err = lex.peek(); err == nil {
+			if _, val, err = parseValue(lex); err == nil {
+				return splice(data, lxm.BytePos, lxm.BytePos+len(val), value), nil
+			}
+		}
+	}
+	if err != nil {
+		return nil, withPath(err, formatPath(path))
+	}
+	return insertMember(data, loc, path, value)
+}
+
+// insertMember returns copy of data with the member missing at loc, intermediate objects are created
+func insertMember(data []byte, loc location, path []string, value []byte) ([]byte, error) {
+	var member []byte
+	if !loc.empty {
+		member = append(member, ',')
+	}
+	member, err := appendMembers(member, path[loc.missing:], value)
+	if err != nil {
+		return nil, bindError(err, data, data[loc.start:], formatPath(path))
+	}
+	return splice(data, loc.start, loc.start, member), nil
+}
+
+// addMember returns copy of data with member added to the object where loc is missing
+func addMember(data []byte, loc location, key, value []byte) []byte {
+	var member []byte
+	if !loc.empty {
+		member = append(member, ',')
+	}
+	return splice(data, loc.start, loc.start, appendMember(member, key, value))
+}
+
+// appendMember appends member with key and value, key is quoted
+func appendMember(buf, key, value []byte) []byte {
+	buf = appendQuote(buf, key)
+	buf = append(buf, ':')
+	return append(buf, value...)
+}
+
+// location is the result of locate, when a member is missing start is the offset
+// where it can be inserted into its object
+type location struct {
+	start int
+	// missing is the index of the first missing part of path, it is -1 when the value is found
+	missing int
+	// empty reports whether the object of missing member has no members
+	empty bool
+}
+
+// locate skips path up to the value, only the last object on the way may miss the member.
+// index parses path part when array is encountered
+func locate(lex *Tokenizer, path []string, index func(string) (int, bool)) (location, error) {
+	for i, part := range path {
+		lxm, err := lex.peek()
+		if err != nil {
+			return location{}, err
+		}
+		if lxm.Type != OpenCurve {
+			if err := skipPathPart(lex, part, index); err != nil {
+				return location{}, err
+			}
+			continue
+		}
+		_, _ = lex.next()
+		found, end, err := findMember(lex, []byte(part), lxm.BytePos+1)
+		if err != nil {
+			return location{}, err
+		} else if !found {
+			return location{start: end, missing: i, empty: end == lxm.BytePos+1}, nil
+		}
+	}
+	return location{missing: -1}, nil
+}
+
+// findMember skips the rest of object until the member with key and reports whether it is found,
+// end is the offset after the last value of object or start when the object is empty
+func findMember(lex *Tokenizer, key []byte, start int) (bool, int, error) {
+	//first bracket is skipped
+	end := start
+	lxm, err := lex.next()
+	if err != nil || lxm.Type == CloseCurve {
+		return false, end, err
+	}
+	for {
+		if found, err := matchKey(lex, lxm, key); err != nil || found {
+			return found, end, err
+		}
+		if lxm, err = lex.peek(); err != nil {
+			return false, end, err
+		}
+		_, val, err := parseValue(lex)
+		if err != nil {
+			return false, end, err
+		}
+		end = lxm.BytePos + len(val)
+		if closed, err := skipSeparator(lex, CloseCurve); err != nil || closed {
+			return false, end, err
+		}
+		if lxm, err = lex.next(); err != nil {
+			return false, end, err
+		}
+	}
+}
+
+// appendMembers appends member with key path[0] and value wrapped into objects with keys of the rest of path
+func appendMembers(buf []byte, path []string, value []byte) ([]byte, error) {
+	for _, part := range path {
+		if _, ok := pathIndex(part); ok {
+			return nil, ErrorWrongPath
+		}
+	}
+	for i, part := range path {
+		if i > 0 {
+			buf = append(buf, '{')
+		}
+		buf = appendQuote(buf, []byte(part))
+		buf = append(buf, ':')
+	}
+	buf = append(buf, value...)
+	for i := 1; i < len(path); i++ {
+		buf = append(buf, '}')
+	}
+	return buf, nil
+}
+
+// splice returns copy of data where data[start:end] is replaced with value
+func splice(data []byte, start, end int, value []byte) []byte {
+	ret := make([]byte, 0, len(data)-(end-start)+len(value))
+	ret = append(ret, data[:start]...)
+	ret = append(ret, value...)
+	return append(ret, data[end:]...)
+}
+
+// Delete returns copy of data without the object member or array element found by path
+func Delete(data []byte, path ...string) ([]byte, error) {
+	return defaultParser.Delete(data, path...)
+}
+
+// Delete returns copy of data without the object member or array element found by path, untouched bytes
+// are preserved. The comma after the removed value is removed as well, or the one before it when it is the last.
+// Data must be valid JSON, ErrorWrongPath is returned when the value does not exist or path is empty
+func (p *Parser) Delete(data []byte, path ...string) ([]byte, error) {
+	if err := p.Validate(data); err != nil {
+		return nil, err
+	} else if len(path) == 0 {
+		return nil, ErrorWrongPath
+	}
+	lex := p.NewTokenizer(data)
+	if err := skipPath(lex, path[:len(path)-1]); err != nil {
+		return nil, withPath(err, formatPath(path))
+	}
+	span, err := findChild(lex, path[len(path)-1], pathIndex)
+	if err != nil {
+		return nil, withPath(err, formatPath(path))
+	}
+	return removeChild(data, span), nil
+}
+
+// removeChild returns copy of data without the child and the comma after it or before it
+func removeChild(data []byte, span childSpan) []byte {
+	switch {
+	case span.next >= 0:
+		return splice(data, span.start, span.next, nil)
+	case span.prevEnd >= 0:
+		return splice(data, span.prevEnd, span.end, nil)
+	}
+	return splice(data, span.start, span.end, nil)
+}
+
+// childSpan is the position of object member or array element, start is the offset of key for members
+type childSpan struct {
+	start, end int
+	// prevEnd is the offset after the previous value, it is -1 for the first child
+	prevEnd int
+	// next is the offset of the token after the comma following the child, it is -1 when there is no comma
+	next int
+}
+
+// findChild finds member or element of the next value by path part like skipPathPart does
+func findChild(lex *Tokenizer, part string, index func(string) (int, bool)) (childSpan, error) {
+	open, err := lex.next()
+	if err != nil {
+		return childSpan{}, err
+	}
+	target, closeLex := -1, CloseCurve
+	switch open.Type {
+	case OpenCurve:
+	case OpenBracket:
+		i, ok := index(part)
+		if !ok {
+			return childSpan{}, lex.lexemeError(ErrorWrongPath.withTypes(Object, Array), open)
+		}
+		if i < 0 {
+			saved := lex.save(open.BytePos)
+			count, err := countElements(lex)
+			if err != nil {
+				return childSpan{}, err
+			}
+			lex.rewind(saved)
+			i += count
+			if i < 0 {
+				return childSpan{}, lex.lexemeError(ErrorWrongPath, open)
+			}
+		}
+		target, closeLex = i, CloseBracket
+	case String, Int, Float, Bool, Null:
+		expected := Object
+		if _, ok := index(part); ok {
+			expected = Array
+		}
+		return childSpan{}, lex.lexemeError(ErrorWrongPath.withTypes(expected, open.Type), open)
+	default:
+		return childSpan{}, lex.lexemeError(ErrorUnexpectedLexeme, open)
+	}
+
+	lxm, err := lex.peek()
+	if err != nil {
+		return childSpan{}, err
+	}
+	if lxm.Type == closeLex {
+		return childSpan{}, lex.lexemeError(ErrorWrongPath, lxm)
+	}
+	span := childSpan{prevEnd: -1, next: -1}
+	for i := 0; ; i++ {
+		if lxm, err = lex.peek(); err != nil {
+			return childSpan{}, err
+		}
+		span.start = lxm.BytePos
+		found := i == target
+		if closeLex == CloseCurve {
+			_, _ = lex.next()
+			if found, err = matchKey(lex, lxm, []byte(part)); err != nil {
+				return childSpan{}, err
+			}
+			if lxm, err = lex.peek(); err != nil {
+				return childSpan{}, err
+			}
+		}
+		_, val, err := parseValue(lex)
+		if err != nil {
+			return childSpan{}, err
+		}
+		span.end = lxm.BytePos + len(val)
+
+		sep, err := lex.next()
+		if err != nil {
+			return childSpan{}, err
+		}
+		switch {
+		case sep.Type == closeLex && found:
+			return span, nil
+		case sep.Type == closeLex:
+			return childSpan{}, lex.lexemeError(ErrorWrongPath, sep)
+		case sep.Type != Comma:
+			return childSpan{}, lex.lexemeError(ErrorUnexpectedLexeme, sep)
+		}
+		next, err := lex.peek()
+		if err != nil {
+			return childSpan{}, err
+		}
+		if found {
+			span.next = next.BytePos
+			return span, nil
+		} else if next.Type == closeLex && lex.trailingCommas {
+			return childSpan{}, lex.lexemeError(ErrorWrongPath, next)
+		}
+		span.prevEnd = span.end
+	}
+}
+
+// ArrayAppend returns copy of data with value added to the end of the array found by path
+func ArrayAppend(data, value []byte, path ...string) ([]byte, error) {
+	return defaultParser.ArrayAppend(data, value, path...)
+}
+
+// ArrayPrepend returns copy of data with value added to the beginning of the array found by path
+func ArrayPrepend(data, value []byte, path ...string) ([]byte, error) {
+	return defaultParser.ArrayPrepend(data, value, path...)
+}
+
+// ArrayInsert returns copy of data with value inserted at index of the array found by path
+func ArrayInsert(data, value []byte, index int, path ...string) ([]byte, error) {
+	return defaultParser.ArrayInsert(data, value, index, path...)
+}
+
+// ArrayAppend returns copy of data with value added to the end of the array found by path
+func (p *Parser) ArrayAppend(data, value []byte, path ...string) ([]byte, error) {
+	return p.ArrayInsert(data, value, math.MaxInt, path...)
+}
+
+// ArrayPrepend returns copy of data with value added to the beginning of the array found by path
+func (p *Parser) ArrayPrepend(data, value []byte, path ...string) ([]byte, error) {
+	return p.ArrayInsert(data, value, 0, path...)
+}
+
+// ArrayInsert returns copy of data with value inserted at index of the array found by path, untouched bytes
+// are preserved. Negative index counts from the end, index beyond the end appends value. When path is missing
+// the array with value is created like Set does. Data and value must be valid JSON
+func (p *Parser) ArrayInsert(data, value []byte, index int, path ...string) ([]byte, error) {
+	if err := p.Validate(data); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(value); err != nil {
+		return nil, err
+	}
+	lex := p.NewTokenizer(data)
+	loc, err := locate(lex, path, pathIndex)
+	if err != nil {
+		return nil, withPath(err, formatPath(path))
+	} else if loc.missing >= 0 {
+		array := append(append([]byte{'['}, value...), ']')
+		return insertMember(data, loc, path, array)
+	}
+	ret, err := insertElement(lex, data, value, index, true)
+	if err != nil {
+		return nil, withPath(err, formatPath(path))
+	}
+	return ret, nil
+}
+
+// insertElement returns copy of data with value inserted into the next array of lex,
+// index beyond the end appends value when appendBeyond is set and it is ErrorWrongPath otherwise
+func insertElement(lex *Tokenizer, data, value []byte, index int, appendBeyond bool) ([]byte, error) {
+	open, err := openContainer(lex, OpenBracket)
+	if err != nil {
+		return nil, err
+	}
+	var starts, ends []int
+	lxm, err := lex.peek()
+	if err != nil {
+		return nil, err
+	}
+	if lxm.Type == CloseBracket {
+		_, _ = lex.next()
+		if index > 0 && !appendBeyond {
+			return nil, lex.lexemeError(ErrorWrongPath, lxm)
+		}
+		if inner := data[open.BytePos+1 : lxm.BytePos]; len(bytes.TrimSpace(inner)) == 0 {
+			// whitespace of empty array is replaced
+			return splice(data, open.BytePos+1, lxm.BytePos, value), nil
+		}
+		return splice(data, open.BytePos+1, open.BytePos+1, value), nil
+	}
+	for {
+		if lxm, err = lex.peek(); err != nil {
+			return nil, err
+		}
+		_, val, err := parseValue(lex)
+		if err != nil {
+			return nil, err
+		}
+		starts = append(starts, lxm.BytePos)
+		ends = append(ends, lxm.BytePos+len(val))
+		if closed, err := skipSeparator(lex, CloseBracket); err != nil {
+			return nil, err
+		} else if closed {
+			break
+		}
+	}
+
+	// the separator between the first elements is reused when it is only comma and whitespace,
+	// the single element is separated by comma followed by whitespace before it
+	sep := []byte{','}
+	if len(starts) > 1 {
+		if s := data[ends[0]:starts[1]]; bytes.Equal(bytes.TrimSpace(s), sep) {
+			sep = s
+		}
+	} else if s := data[open.BytePos+1 : starts[0]]; len(bytes.TrimSpace(s)) == 0 {
+		sep = append(sep, s...)
+	}
+	if index < 0 {
+		index += len(starts)
+		if index < 0 {
+			return nil, lex.lexemeError(ErrorWrongPath, open)
+		}
+	}
+	if index > len(starts) && !appendBeyond {
+		return nil, lex.lexemeError(ErrorWrongPath, open)
+	} else if index >= len(starts) {
+		last := ends[len(ends)-1]
+		return splice(data, last, last, append(append([]byte(nil), sep...), value...)), nil
+	}
+	return splice(data, starts[index], starts[index], append(append([]byte(nil), value...), sep...)), nil
+}
@@ -0,0 +1,253 @@
+package jajson_test
+
+import (
+	"testing"
+
+	"github.com/aleksandrzhukovskii/jajson"
+	"github.com/stretchr/testify/suite"
+)
+
+type ModifySuite struct {
+	suite.Suite
+}
+
+func TestModify(t *testing.T) {
+	suite.Run(t, new(ModifySuite))
+}
+
+func (t *ModifySuite) TestSet() {
+	data := []byte("{\n  \"a\": 1,\n  \"b\": {\"c\": [1, 2, 3]},\n  \"e\": {}\n}")
+	tests := []struct {
+		path     []string
+		value    string
+		expected string
+	}{
+		{path: []string{"a"}, value: `"x"`, expected: "{\n  \"a\": \"x\",\n  \"b\": {\"c\": [1, 2, 3]},\n  \"e\": {}\n}"},
+		{path: []string{"b", "c", "[1]"}, value: `{"d": null}`, expected: "{\n  \"a\": 1,\n  \"b\": {\"c\": [1, {\"d\": null}, 3]},\n  \"e\": {}\n}"},
+		{path: []string{"b", "c", "[-1]"}, value: `4`, expected: "{\n  \"a\": 1,\n  \"b\": {\"c\": [1, 2, 4]},\n  \"e\": {}\n}"},
+		{path: []string{"b"}, value: `[]`, expected: "{\n  \"a\": 1,\n  \"b\": [],\n  \"e\": {}\n}"},
+		{path: []string{"f"}, value: `true`, expected: "{\n  \"a\": 1,\n  \"b\": {\"c\": [1, 2, 3]},\n  \"e\": {},\"f\":true\n}"},
+		{path: []string{"e", "x"}, value: `1`, expected: "{\n  \"a\": 1,\n  \"b\": {\"c\": [1, 2, 3]},\n  \"e\": {\"x\":1}\n}"},
+		{path: []string{"b", "x\"", "y", "z"}, value: `1`, expected: "{\n  \"a\": 1,\n  \"b\": {\"c\": [1, 2, 3],\"x\\\"\":{\"y\":{\"z\":1}}},\n  \"e\": {}\n}"},
+		{path: nil, value: `null`, expected: `null`},
+	}
+	for _, test := range tests {
+		ret, err := jajson.Set(data, []byte(test.value), test.path...)
+		t.Require().NoError(err, test.path)
+		t.Equal(test.expected, string(ret), test.path)
+		t.True(jajson.Valid(ret), test.path)
+	}
+	t.Equal("{\n  \"a\": 1,\n  \"b\": {\"c\": [1, 2, 3]},\n  \"e\": {}\n}", string(data))
+}
+
+func (t *ModifySuite) TestSetOptions() {
+	p := jajson.Parser{JSON5: true}
+	ret, err := p.Set([]byte(`{a: 1, /* c */ b: 2, // d
+}`), []byte(`'x'`), "c")
+	t.Require().NoError(err)
+	t.Equal("{a: 1, /* c */ b: 2,\"c\":'x', // d\n}", string(ret))
+	t.True(p.Valid(ret))
+
+	ret, err = p.Set([]byte(`{a: 1,}`), []byte(`2`), "b")
+	t.Require().NoError(err)
+	t.Equal(`{a: 1,"b":2,}`, string(ret))
+}
+
+func (t *ModifySuite) TestSetErrors() {
+	data := []byte(`{"a": 1, "b": [1]}`)
+
+	_, err := jajson.Set(data, []byte(`{"x": }`), "a")
+	t.ErrorIs(err, jajson.ErrorUnexpectedLexeme)
+
+	_, err = jajson.Set(data, []byte(`1`), "a", "c")
+	t.ErrorIs(err, jajson.ErrorWrongPath)
+	var e jajson.Error
+	t.Require().ErrorAs(err, &e)
+	t.Equal(jajson.Object, e.Expected())
+	t.Equal(jajson.Int, e.Actual())
+	t.Equal("a.c", e.Path())
+
+	_, err = jajson.Set(data, []byte(`1`), "b", "[1]")
+	t.ErrorIs(err, jajson.ErrorWrongPath)
+
+	_, err = jajson.Set(data, []byte(`1`), "c", "[0]")
+	t.ErrorIs(err, jajson.ErrorWrongPath)
+	t.Require().ErrorAs(err, &e)
+	t.Equal(17, e.Pos())
+	t.Equal("c[0]", e.Path())
+
+	_, err = jajson.Set([]byte(`{"a": tru}`), []byte(`1`), "b")
+	t.ErrorIs(err, jajson.ErrorUnexpected)
+
+	_, err = jajson.Set(nil, []byte(`1`), "a")
+	t.ErrorIs(err, jajson.ErrorEmptyJSON)
+
+	// malformed documents are rejected even when the path is found before the error
+	_, err = jajson.Set([]byte(`{"a":1} trailing`), []byte(`2`), "a")
+	t.ErrorIs(err, jajson.ErrorTrailingData)
+	_, err = jajson.Set([]byte(`{"a":5, "b": }`), []byte(`2`), "a")
+	t.ErrorIs(err, jajson.ErrorUnexpectedLexeme)
+}
+
+func (t *ModifySuite) TestDelete() {
+	tests := []struct {
+		data     string
+		path     []string
+		expected string
+	}{
+		{data: `{"a": 1, "b": 2, "c": 3}`, path: []string{"a"}, expected: `{"b": 2, "c": 3}`},
+		{data: `{"a": 1, "b": 2, "c": 3}`, path: []string{"b"}, expected: `{"a": 1, "c": 3}`},
+		{data: `{"a": 1, "b": 2, "c": 3}`, path: []string{"c"}, expected: `{"a": 1, "b": 2}`},
+		{data: `{ "a": 1 }`, path: []string{"a"}, expected: `{  }`},
+		{data: "{\n  \"a\": 1,\n  \"b\": {\"x\": [1, 2]}\n}", path: []string{"b"}, expected: "{\n  \"a\": 1\n}"},
+		{data: `{"a": {"x": [1, {"y": 2}, 3]}}`, path: []string{"a", "x", "[1]"}, expected: `{"a": {"x": [1, 3]}}`},
+		{data: `{"a": {"x": [1, {"y": 2}, 3]}}`, path: []string{"a", "x", "[0]"}, expected: `{"a": {"x": [{"y": 2}, 3]}}`},
+		{data: `{"a": {"x": [1, {"y": 2}, 3]}}`, path: []string{"a", "x", "[-1]"}, expected: `{"a": {"x": [1, {"y": 2}]}}`},
+		{data: `{"a": {"x": [1, {"y": 2}, 3]}}`, path: []string{"a", "x", "[1]", "y"}, expected: `{"a": {"x": [1, {}, 3]}}`},
+		{data: `[[]]`, path: []string{"[0]"}, expected: `[]`},
+		{data: `{"a\"b": 1, "a": 2}`, path: []string{`a"b`}, expected: `{"a": 2}`},
+	}
+	for _, test := range tests {
+		ret, err := jajson.Delete([]byte(test.data), test.path...)
+		t.Require().NoError(err, test.path)
+		t.Equal(test.expected, string(ret), test.path)
+		t.True(jajson.Valid(ret), test.path)
+	}
+}
+
+func (t *ModifySuite) TestDeleteOptions() {
+	p := jajson.Parser{JSON5: true}
+	tests := []struct {
+		data     string
+		path     []string
+		expected string
+	}{
+		{data: `{a: 1, b: 2,}`, path: []string{"b"}, expected: `{a: 1, }`},
+		{data: `{a: 1,}`, path: []string{"a"}, expected: `{}`},
+		{data: `[1, 2,]`, path: []string{"[-1]"}, expected: `[1, ]`},
+		{data: `{a: 1, /* c */ b: 2}`, path: []string{"b"}, expected: `{a: 1}`},
+	}
+	for _, test := range tests {
+		ret, err := p.Delete([]byte(test.data), test.path...)
+		t.Require().NoError(err, test.path)
+		t.Equal(test.expected, string(ret), test.path)
+		t.True(p.Valid(ret), test.path)
+	}
+	_, err := p.Delete([]byte(`{a: 1,}`), "b")
+	t.ErrorIs(err, jajson.ErrorWrongPath)
+}
+
+func (t *ModifySuite) TestDeleteErrors() {
+	data := []byte(`{"a": 1, "b": [1]}`)
+	tests := []struct {
+		path []string
+		err  error
+		pos  int
+	}{
+		{path: []string{"c"}, err: jajson.ErrorWrongPath, pos: 17},
+		{path: []string{"b", "[1]"}, err: jajson.ErrorWrongPath, pos: 16},
+		{path: []string{"b", "[-2]"}, err: jajson.ErrorWrongPath, pos: 14},
+		{path: []string{"b", "x"}, err: jajson.ErrorWrongPath, pos: 14},
+		{path: []string{"a", "x"}, err: jajson.ErrorWrongPath, pos: 6},
+		{path: []string{"c", "x"}, err: jajson.ErrorWrongPath, pos: 17},
+	}
+	for _, test := range tests {
+		_, err := jajson.Delete(data, test.path...)
+		t.ErrorIs(err, test.err, test.path)
+		var e jajson.Error
+		t.Require().ErrorAs(err, &e)
+		t.Equal(test.pos, e.Pos(), test.path)
+	}
+
+	_, err := jajson.Delete(data)
+	t.ErrorIs(err, jajson.ErrorWrongPath)
+	_, err = jajson.Delete([]byte(`{"a": 1 "b": 2}`), "b")
+	t.ErrorIs(err, jajson.ErrorUnexpectedLexeme)
+	_, err = jajson.Delete(nil, "a")
+	t.ErrorIs(err, jajson.ErrorEmptyJSON)
+	_, err = jajson.Delete([]byte(`{"a":1, "b":2 x`), "a")
+	t.ErrorIs(err, jajson.ErrorUnexpected)
+	_, err = jajson.Delete([]byte(`[1, 2] [3]`), "[0]")
+	t.ErrorIs(err, jajson.ErrorTrailingData)
+}
+
+func (t *ModifySuite) TestArrayInsert() {
+	data := []byte(`{"tags": ["a", "b"], "empty": [ ], "one": [1], "n": 1}`)
+	tests := []struct {
+		index    int
+		path     []string
+		expected string
+	}{
+		{index: 0, path: []string{"tags"}, expected: `{"tags": ["x", "a", "b"], "empty": [ ], "one": [1], "n": 1}`},
+		{index: 1, path: []string{"tags"}, expected: `{"tags": ["a", "x", "b"], "empty": [ ], "one": [1], "n": 1}`},
+		{index: 2, path: []string{"tags"}, expected: `{"tags": ["a", "b", "x"], "empty": [ ], "one": [1], "n": 1}`},
+		{index: 5, path: []string{"tags"}, expected: `{"tags": ["a", "b", "x"], "empty": [ ], "one": [1], "n": 1}`},
+		{index: -1, path: []string{"tags"}, expected: `{"tags": ["a", "x", "b"], "empty": [ ], "one": [1], "n": 1}`},
+		{index: 0, path: []string{"empty"}, expected: `{"tags": ["a", "b"], "empty": ["x"], "one": [1], "n": 1}`},
+		{index: 1, path: []string{"one"}, expected: `{"tags": ["a", "b"], "empty": [ ], "one": [1,"x"], "n": 1}`},
+		{index: 0, path: []string{"new", "list"}, expected: `{"tags": ["a", "b"], "empty": [ ], "one": [1], "n": 1,"new":{"list":["x"]}}`},
+	}
+	for _, test := range tests {
+		ret, err := jajson.ArrayInsert(data, []byte(`"x"`), test.index, test.path...)
+		t.Require().NoError(err, test.path)
+		t.Equal(test.expected, string(ret), test.path)
+		t.True(jajson.Valid(ret), test.path)
+	}
+
+	ret, err := jajson.ArrayAppend(data, []byte(`{"k": 1}`), "tags")
+	t.Require().NoError(err)
+	t.Equal(`{"tags": ["a", "b", {"k": 1}], "empty": [ ], "one": [1], "n": 1}`, string(ret))
+
+	ret, err = jajson.ArrayPrepend(data, []byte(`[]`), "one")
+	t.Require().NoError(err)
+	t.Equal(`{"tags": ["a", "b"], "empty": [ ], "one": [[],1], "n": 1}`, string(ret))
+
+	ret, err = jajson.ArrayAppend([]byte("[\n  1,\n  2\n]"), []byte(`3`))
+	t.Require().NoError(err)
+	t.Equal("[\n  1,\n  2,\n  3\n]", string(ret))
+
+	ret, err = jajson.ArrayAppend([]byte("[\n  1\n]"), []byte(`2`))
+	t.Require().NoError(err)
+	t.Equal("[\n  1,\n  2\n]", string(ret))
+	ret, err = jajson.ArrayPrepend([]byte("[\n  1\n]"), []byte(`0`))
+	t.Require().NoError(err)
+	t.Equal("[\n  0,\n  1\n]", string(ret))
+	ret, err = jajson.ArrayAppend([]byte(`[ 1 ]`), []byte(`2`))
+	t.Require().NoError(err)
+	t.Equal(`[ 1, 2 ]`, string(ret))
+
+	p := jajson.Parser{JSON5: true}
+	ret, err = p.ArrayAppend([]byte(`[1, 2,]`), []byte(`3`))
+	t.Require().NoError(err)
+	t.Equal(`[1, 2, 3,]`, string(ret))
+	ret, err = p.ArrayAppend([]byte(`[/* c */]`), []byte(`3`))
+	t.Require().NoError(err)
+	t.Equal(`[3/* c */]`, string(ret))
+}
+
+func (t *ModifySuite) TestArrayInsertErrors() {
+	data := []byte(`{"tags": ["a"], "n": 1}`)
+
+	_, err := jajson.ArrayAppend(data, []byte(`1`), "n")
+	t.ErrorIs(err, jajson.ErrorWrongValueType)
+	var e jajson.Error
+	t.Require().ErrorAs(err, &e)
+	t.Equal(jajson.Array, e.Expected())
+	t.Equal(jajson.Int, e.Actual())
+	t.Equal("n", e.Path())
+
+	_, err = jajson.ArrayInsert(data, []byte(`1`), -2, "tags")
+	t.ErrorIs(err, jajson.ErrorWrongPath)
+
+	_, err = jajson.ArrayAppend(data, []byte(`1,`), "tags")
+	t.ErrorIs(err, jajson.ErrorTrailingData)
+
+	_, err = jajson.ArrayAppend([]byte(`{"tags": ["a" "b"]}`), []byte(`1`), "tags")
+	t.ErrorIs(err, jajson.ErrorUnexpectedLexeme)
+	_, err = jajson.ArrayAppend([]byte(`{"tags":[1] ] }`), []byte(`2`), "tags")
+	t.ErrorIs(err, jajson.ErrorUnexpectedLexeme)
+	_, err = jajson.ArrayPrepend([]byte(`[1] x`), []byte(`2`))
+	t.ErrorIs(err, jajson.ErrorTrailingData)
+	_, err = jajson.ArrayAppend(nil, []byte(`2`))
+	t.ErrorIs(err, jajson.ErrorEmptyJSON)
+}
@@ -0,0 +1,147 @@
+package jajson
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// NDJSONPolicy selects what NDJSONReader does with lines which are not valid JSON
+type NDJSONPolicy uint8
+
+const (
+	// NDJSONStop stops reading at the first invalid line, its error is returned by Err
+	NDJSONStop NDJSONPolicy = iota
+	// NDJSONSkip skips invalid lines
+	NDJSONSkip
+	// NDJSONCollect skips invalid lines and collects their errors, they are returned by Errors
+	NDJSONCollect
+)
+
+// Record is a line of NDJSON with a valid JSON value
+type Record struct {
+	// Line is 1-based line of the record in the input
+	Line int
+	// Value is the line without line terminator, it is valid only until the next call of NDJSONReader.Next
+	Value  []byte
+	parser *Parser
+}
+
+// NDJSONReader reads NDJSON (JSON Lines) record by record, empty lines are skipped.
+// Line of errors is the line in the input, their positions are offsets in the line
+type NDJSONReader struct {
+	parser *Parser
+	reader *bufio.Reader
+	policy NDJSONPolicy
+	buf    []byte
+	record Record
+	err    error
+	errors []error
+}
+
+// NewNDJSONReader returns reader of NDJSON records from r
+func NewNDJSONReader(r io.Reader, policy NDJSONPolicy) *NDJSONReader {
+	return defaultParser.NewNDJSONReader(r, policy)
+}
+
+// NewNDJSONReader returns reader of NDJSON records from r
+func (p *Parser) NewNDJSONReader(r io.Reader, policy NDJSONPolicy) *NDJSONReader {
+	return &NDJSONReader{parser: p, reader: bufio.NewReader(r), policy: policy, record: Record{parser: p}}
+}
+
+// Next reads the next valid record and reports whether it is read
+func (r *NDJSONReader) Next() bool {
+	for r.err == nil {
+		line, err := r.readLine()
+		if len(line) == 0 && err != nil {
+			if err != io.EOF {
+				r.err = err
+			}
+			return false
+		}
+		r.record.Line++
+		if len(bytes.Trim(line, " \t\r")) == 0 {
+			continue
+		}
+		if verr := r.parser.Validate(line); verr != nil {
+			verr = atLine(verr, r.record.Line)
+			switch r.policy {
+			case NDJSONStop:
+				r.err = verr
+				return false
+			case NDJSONCollect:
+				r.errors = append(r.errors, verr)
+			}
+			continue
+		}
+		r.record.Value = line
+		return true
+	}
+	return false
+}
+
+// readLine returns the next line without line terminator
+func (r *NDJSONReader) readLine() ([]byte, error) {
+	r.buf = r.buf[:0]
+	for {
+		line, err := r.reader.ReadSlice('\n')
+		r.buf = append(r.buf, line...)
+		if err != bufio.ErrBufferFull {
+			line = bytes.TrimSuffix(r.buf, []byte{'\n'})
+			return bytes.TrimSuffix(line, []byte{'\r'}), err
+		}
+	}
+}
+
+// Record returns the record read by the last call of Next
+func (r *NDJSONReader) Record() Record {
+	return r.record
+}
+
+// Err returns the error which stopped reading, it is nil at the end of input
+func (r *NDJSONReader) Err() error {
+	return r.err
+}
+
+// Errors returns errors of invalid lines collected with NDJSONCollect policy
+func (r *NDJSONReader) Errors() []error {
+	return r.errors
+}
+
+// GetRawValue returns part of the record with value
+func (rec Record) GetRawValue(path ...string) (LexemeType, []byte, error) {
+	typ, val, err := rec.parser.GetRawValue(rec.Value, path...)
+	return typ, val, atLine(err, rec.Line)
+}
+
+// IsNull reports whether the value is the null literal
+func (rec Record) IsNull(path ...string) (bool, error) {
+	ret, err := rec.parser.IsNull(rec.Value, path...)
+	return ret, atLine(err, rec.Line)
+}
+
+// GetString returns the unquoted string, ErrorNull is returned for null
+func (rec Record) GetString(path ...string) (string, error) {
+	ret, err := rec.parser.GetString(rec.Value, path...)
+	return ret, atLine(err, rec.Line)
+}
+
+func (rec Record) GetBool(path ...string) (bool, error) {
+	ret, err := rec.parser.GetBool(rec.Value, path...)
+	return ret, atLine(err, rec.Line)
+}
+
+func (rec Record) GetInt(path ...string) (int64, error) {
+	ret, err := rec.parser.GetInt(rec.Value, path...)
+	return ret, atLine(err, rec.Line)
+}
+
+func (rec Record) GetUInt(path ...string) (uint64, error) {
+	ret, err := rec.parser.GetUInt(rec.Value, path...)
+	return ret, atLine(err, rec.Line)
+}
+
+func (rec Record) GetFloat(path ...string) (float64, error) {
+	ret, err := rec.parser.GetFloat(rec.Value, path...)
+	return ret, atLine(err, rec.Line)
+}
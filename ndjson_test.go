@@ -0,0 +1,143 @@
+package jajson_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/aleksandrzhukovskii/jajson"
+	"github.com/stretchr/testify/suite"
+)
+
+type NDJSONSuite struct {
+	suite.Suite
+}
+
+func TestNDJSON(t *testing.T) {
+	suite.Run(t, new(NDJSONSuite))
+}
+
+const ndjson = "{\"level\": \"info\", \"msg\": \"started\", \"ms\": 12}\r\n" +
+	"\n" +
+	"{\"level\": \"warn\", \"msg\": \"slow\"\n" +
+	"   {\"level\": \"error\", \"msg\": \"failed\", \"ms\": 1.5}  \n" +
+	"[1, 2] 3\n" +
+	"{\"level\": \"info\", \"msg\": \"done\"}"
+
+func (t *NDJSONSuite) TestSkip() {
+	r := jajson.NewNDJSONReader(strings.NewReader(ndjson), jajson.NDJSONSkip)
+	var lines []int
+	var levels []string
+	for r.Next() {
+		rec := r.Record()
+		lines = append(lines, rec.Line)
+		level, err := rec.GetString("level")
+		t.NoError(err)
+		levels = append(levels, level)
+	}
+	t.NoError(r.Err())
+	t.Empty(r.Errors())
+	t.Equal([]int{1, 4, 6}, lines)
+	t.Equal([]string{"info", "error", "info"}, levels)
+}
+
+func (t *NDJSONSuite) TestCollect() {
+	r := jajson.NewNDJSONReader(iotest.OneByteReader(strings.NewReader(ndjson)), jajson.NDJSONCollect)
+	count := 0
+	for r.Next() {
+		count++
+	}
+	t.NoError(r.Err())
+	t.Equal(3, count)
+	t.Require().Len(r.Errors(), 2)
+
+	var e jajson.Error
+	t.Require().ErrorAs(r.Errors()[0], &e)
+	t.ErrorIs(e, jajson.ErrorUnexpected)
+	t.Equal(3, e.Line())
+	t.Equal(32, e.Column())
+
+	t.Require().ErrorAs(r.Errors()[1], &e)
+	t.ErrorIs(e, jajson.ErrorTrailingData)
+	t.Equal(5, e.Line())
+	t.Equal(8, e.Column())
+}
+
+func (t *NDJSONSuite) TestStop() {
+	r := jajson.NewNDJSONReader(strings.NewReader(ndjson), jajson.NDJSONStop)
+	t.True(r.Next())
+	t.Equal(1, r.Record().Line)
+	t.Equal(`{"level": "info", "msg": "started", "ms": 12}`, string(r.Record().Value))
+	t.False(r.Next())
+	t.ErrorIs(r.Err(), jajson.ErrorUnexpected)
+	t.False(r.Next())
+
+	r = jajson.NewNDJSONReader(strings.NewReader("\v\n\u00a0{}\n"), jajson.NDJSONCollect)
+	t.False(r.Next())
+	t.Len(r.Errors(), 2)
+
+	// errors do not change when the buffer of the line is reused
+	r = jajson.NewNDJSONReader(strings.NewReader("{\"a\": [1, 2, 3]} x\n{\"b\":2}\n{}\n"), jajson.NDJSONCollect)
+	t.True(r.Next())
+	_, err := r.Record().GetBool("b")
+	t.True(r.Next())
+	t.False(r.Next())
+	t.Require().Len(r.Errors(), 1)
+	t.Equal("Line: 1. Column: 18. Pos: 17. Error: unexpected data after JSON value", r.Errors()[0].Error())
+	t.Equal("Line: 2. Column: 6. Pos: 5. Path: b. Expected: Bool. Actual: Int. Error: wrong type of value", err.Error())
+	var e jajson.Error
+	t.Require().ErrorAs(r.Errors()[0], &e)
+	t.Equal("{\"a\": [1, 2, 3]} x\n                 ^", e.Context())
+
+	readErr := errors.New("read failed")
+	r = jajson.NewNDJSONReader(iotest.ErrReader(readErr), jajson.NDJSONSkip)
+	t.False(r.Next())
+	t.Equal(readErr, r.Err())
+}
+
+func (t *NDJSONSuite) TestRecordGetters() {
+	parser := jajson.Parser{Comments: true}
+	r := parser.NewNDJSONReader(strings.NewReader("\n{\"id\": 7, \"ok\": true, \"v\": -1.5, \"n\": null} // c\n"+
+		strings.Repeat(" ", 5000)+"{\"id\": \"x\"}"), jajson.NDJSONStop)
+	t.Require().True(r.Next())
+	rec := r.Record()
+	t.Equal(2, rec.Line)
+
+	id, err := rec.GetInt("id")
+	t.NoError(err)
+	t.Equal(int64(7), id)
+	uid, err := rec.GetUInt("id")
+	t.NoError(err)
+	t.Equal(uint64(7), uid)
+	ok, err := rec.GetBool("ok")
+	t.NoError(err)
+	t.True(ok)
+	v, err := rec.GetFloat("v")
+	t.NoError(err)
+	t.Equal(-1.5, v)
+	null, err := rec.IsNull("n")
+	t.NoError(err)
+	t.True(null)
+	typ, val, err := rec.GetRawValue()
+	t.NoError(err)
+	t.Equal(jajson.Object, typ)
+	t.Equal(`{"id": 7, "ok": true, "v": -1.5, "n": null}`, string(val))
+
+	_, err = rec.GetString("id")
+	var e jajson.Error
+	t.Require().ErrorAs(err, &e)
+	t.ErrorIs(err, jajson.ErrorWrongValueType)
+	t.Equal(2, e.Line())
+	t.Equal(8, e.Column())
+
+	t.Require().True(r.Next())
+	rec = r.Record()
+	t.Equal(3, rec.Line)
+	_, err = rec.GetInt("id")
+	t.Require().ErrorAs(err, &e)
+	t.Equal(3, e.Line())
+	t.Equal(5008, e.Column())
+	t.False(r.Next())
+	t.NoError(r.Err())
+}
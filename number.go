@@ -0,0 +1,198 @@
+package jajson
+
+import "strconv"
+
+func isFloatSyntax(num []byte) bool {
+	for _, c := range num {
+		if c == '.' || c == 'e' || c == 'E' {
+			return true
+		}
+	}
+	return false
+}
+
+// integralDigits appends to buf plain integer digits of the number written with fraction or exponent,
+// for example 1.5e1 becomes 15, the number must represent an integer
+func integralDigits(num []byte, buf []byte) ([]byte, error) {
+	syntaxErr := &strconv.NumError{Func: "ParseInt", Num: string(num), Err: strconv.ErrSyntax}
+	i := 0
+	if i < len(num) && num[i] == '-' {
+		buf = append(buf, '-')
+		i++
+	}
+	intStart := i
+	for i < len(num) && isDigit(num[i]) {
+		i++
+	}
+	intPart := num[intStart:i]
+	var fracPart []byte
+	if i < len(num) && num[i] == '.' {
+		i++
+		fracStart := i
+		for i < len(num) && isDigit(num[i]) {
+			i++
+		}
+		fracPart = num[fracStart:i]
+	}
+	if len(intPart)+len(fracPart) == 0 {
+		return nil, syntaxErr
+	}
+	exp := 0
+	if i < len(num) && (num[i] == 'e' || num[i] == 'E') {
+		i++
+		neg := false
+		if i < len(num) && (num[i] == '+' || num[i] == '-') {
+			neg = num[i] == '-'
+			i++
+		}
+		if i == len(num) {
+			return nil, syntaxErr
+		}
+		for ; i < len(num) && isDigit(num[i]); i++ {
+			if exp < 10000 {
+				exp = exp*10 + int(num[i]-'0')
+			}
+		}
+		if neg {
+			exp = -exp
+		}
+	}
+	if i != len(num) {
+		return nil, syntaxErr
+	}
+
+	digit := func(j int) byte {
+		if j < len(intPart) {
+			return intPart[j]
+		} else if j-len(intPart) < len(fracPart) {
+			return fracPart[j-len(intPart)]
+		}
+		return '0'
+	}
+	mantissa := len(intPart) + len(fracPart)
+	point := len(intPart) + exp
+	for j := point; j < mantissa; j++ {
+		if j >= 0 && digit(j) != '0' {
+			return nil, ErrorWrongValueType.withTypes(Int, Float)
+		}
+	}
+	first := 0
+	for first < point && digit(first) == '0' {
+		first++
+	}
+	if point-first > 20 {
+		return nil, &strconv.NumError{Func: "ParseInt", Num: string(num), Err: strconv.ErrRange}
+	}
+	if first >= point {
+		return append(buf[:0], '0'), nil
+	}
+	for j := first; j < point; j++ {
+		buf = append(buf, digit(j))
+	}
+	return buf, nil
+}
+
+// parseUint parses decimal digits into unsigned integer of bitSize bits without allocation
+func parseUint(num []byte, bitSize int) (uint64, error) {
+	if len(num) == 0 {
+		return 0, numberSyntaxError("ParseUint", num)
+	}
+	max := uint64(1)<<bitSize - 1
+	var n uint64
+	for _, c := range num {
+		if !isDigit(c) {
+			return 0, numberSyntaxError("ParseUint", num)
+		}
+		d := uint64(c - '0')
+		if n > max/10 || (n == max/10 && d > max%10) {
+			return max, numberRangeError("ParseUint", num)
+		}
+		n = n*10 + d
+	}
+	return n, nil
+}
+
+// parseInt parses optionally signed decimal digits into integer of bitSize bits without allocation
+func parseInt(num []byte, bitSize int) (int64, error) {
+	digits := num
+	neg := false
+	if len(digits) > 0 && (digits[0] == '-' || digits[0] == '+') {
+		neg = digits[0] == '-'
+		digits = digits[1:]
+	}
+	if len(digits) == 0 {
+		return 0, numberSyntaxError("ParseInt", num)
+	}
+	limit := uint64(1) << (bitSize - 1)
+	if !neg {
+		limit--
+	}
+	var n uint64
+	for _, c := range digits {
+		if !isDigit(c) {
+			return 0, numberSyntaxError("ParseInt", num)
+		}
+		d := uint64(c - '0')
+		if n > limit/10 || (n == limit/10 && d > limit%10) {
+			if neg {
+				return -int64(limit), numberRangeError("ParseInt", num)
+			}
+			return int64(limit), numberRangeError("ParseInt", num)
+		}
+		n = n*10 + d
+	}
+	if neg {
+		return -int64(n), nil
+	}
+	return int64(n), nil
+}
+
+func numberSyntaxError(fn string, num []byte) error {
+	e := ErrorNumberSyntax.wrap(&strconv.NumError{Func: fn, Num: string(num), Err: strconv.ErrSyntax})
+	e.literal = string(num)
+	return e
+}
+
+func numberRangeError(fn string, num []byte) error {
+	e := ErrorNumberRange.wrap(&strconv.NumError{Func: fn, Num: string(num), Err: strconv.ErrRange})
+	e.literal = string(num)
+	return e
+}
+
+func isHexSyntax(num []byte) bool {
+	if len(num) > 0 && (num[0] == '-' || num[0] == '+') {
+		num = num[1:]
+	}
+	return len(num) > 2 && num[0] == '0' && (num[1] == 'x' || num[1] == 'X')
+}
+
+// hexDigits appends to buf decimal digits of JSON5 hexadecimal integer, for example -0x1F becomes -31
+func hexDigits(num []byte, buf []byte) ([]byte, error) {
+	i := 0
+	if num[0] == '-' || num[0] == '+' {
+		buf = append(buf, num[0])
+		i++
+	}
+	var v uint64
+	for _, c := range num[i+2:] {
+		x, ok := unhex(c)
+		if !ok {
+			return nil, &strconv.NumError{Func: "ParseInt", Num: string(num), Err: strconv.ErrSyntax}
+		} else if v>>60 != 0 {
+			return nil, &strconv.NumError{Func: "ParseInt", Num: string(num), Err: strconv.ErrRange}
+		}
+		v = v<<4 | uint64(x)
+	}
+	return strconv.AppendUint(buf, v, 10), nil
+}
+
+// normalizeJSON5Number returns JSON5 number in the form accepted by parseFloat
+func normalizeJSON5Number(num []byte, buf []byte) ([]byte, error) {
+	if isHexSyntax(num) {
+		return hexDigits(num, buf)
+	} else if len(num) > 0 && num[0] == '+' {
+		// strconv does not accept +NaN
+		return num[1:], nil
+	}
+	return num, nil
+}
@@ -0,0 +1,207 @@
+package jajson
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type NumberSuite struct {
+	suite.Suite
+}
+
+func TestNumber(t *testing.T) {
+	suite.Run(t, new(NumberSuite))
+}
+
+var intCases = []string{
+	"0", "-0", "+7", "1", "-1", "127", "128", "-128", "-129", "255", "256", "32767", "32768", "-32768", "-32769",
+	"65535", "65536", "2147483647", "2147483648", "-2147483648", "-2147483649", "4294967295", "4294967296",
+	"9223372036854775807", "9223372036854775808", "-9223372036854775808", "-9223372036854775809",
+	"18446744073709551615", "18446744073709551616", "99999999999999999999999", "", "-", "+", "1a", "--1", "1.5",
+}
+
+func (t *NumberSuite) TestParseInt() {
+	for _, bitSize := range []int{8, 16, 32, 64} {
+		for _, test := range intCases {
+			expected, expectedErr := strconv.ParseInt(test, 10, bitSize)
+			val, err := parseInt([]byte(test), bitSize)
+			t.Equal(expected, val, "%s %d", test, bitSize)
+			t.Equal(expectedErr == nil, err == nil, "%s %d", test, bitSize)
+			if expectedErr != nil {
+				t.ErrorIs(err, errors.Unwrap(expectedErr), "%s %d", test, bitSize)
+				t.Equal(test, err.(Error).Literal())
+			}
+		}
+	}
+}
+
+func (t *NumberSuite) TestParseUint() {
+	for _, bitSize := range []int{8, 16, 32, 64} {
+		for _, test := range intCases {
+			expected, expectedErr := strconv.ParseUint(test, 10, bitSize)
+			val, err := parseUint([]byte(test), bitSize)
+			t.Equal(expected, val, "%s %d", test, bitSize)
+			t.Equal(expectedErr == nil, err == nil, "%s %d", test, bitSize)
+			if expectedErr != nil {
+				t.ErrorIs(err, errors.Unwrap(expectedErr), "%s %d", test, bitSize)
+			}
+		}
+	}
+}
+
+func (t *NumberSuite) TestParseIntNoAllocs() {
+	data := []byte(`{"a": -9223372036854775808, "b": "18446744073709551615"}`)
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = GetInt[int64](data, "a")
+		_, _ = GetUInt[uint64](data, "b")
+	})
+	t.Zero(allocs)
+}
+
+var floatCases = []string{
+	"0", "-0", "1", "-1", "0.1", "0.5", "1.5e3", "1E-3", "123456789012345678", "1234567890123456789012345",
+	"0.000000000000000000000000000000000001", "1e22", "1e23", "1.7976931348623157e308", "1.7976931348623159e308",
+	"2.2250738585072011e-308", "4.9406564584124654e-324", "2.4703282292062327e-324", "1e-400", "1e400",
+	"9007199254740993", "9007199254740992.5", "3.4028234663852886e38", "3.4028236e38", "1.17549435e-38",
+	"1.4e-45", "7.038531e-26", "0.000010000000000000000000000000000000000001", "1e0000000000000000000001",
+	"", "-", "1e", "1.5x", "NaN", "+1",
+}
+
+func (t *NumberSuite) TestParseFloat() {
+	for _, bitSize := range []int{32, 64} {
+		for _, test := range floatCases {
+			expected, expectedErr := strconv.ParseFloat(test, bitSize)
+			val, err := parseFloat([]byte(test), bitSize)
+			t.Equal(math.Float64bits(expected), math.Float64bits(val), "%s %d", test, bitSize)
+			t.Equal(expectedErr == nil, err == nil, "%s %d", test, bitSize)
+			if expectedErr != nil {
+				t.ErrorIs(err, errors.Unwrap(expectedErr), "%s %d", test, bitSize)
+			}
+		}
+	}
+}
+
+func (t *NumberSuite) TestParseFloatRandom() {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		var test string
+		switch i % 3 {
+		case 0:
+			test = strconv.FormatFloat(math.Float64frombits(rnd.Uint64()&^(0x7FF<<52)|uint64(rnd.Intn(0x7FF))<<52), 'g', -1, 64)
+		case 1:
+			test = fmt.Sprintf("%de%d", rnd.Int63n(1<<62), rnd.Intn(700)-350)
+		default:
+			test = fmt.Sprintf("%s.%se%d", strings.Repeat("9", rnd.Intn(5)), strconv.FormatUint(rnd.Uint64(), 10)+
+				strconv.FormatUint(rnd.Uint64(), 10), rnd.Intn(80)-40)
+		}
+		for _, bitSize := range []int{32, 64} {
+			expected, _ := strconv.ParseFloat(test, bitSize)
+			val, _ := parseFloat([]byte(test), bitSize)
+			t.Require().Equal(math.Float64bits(expected), math.Float64bits(val), "%s %d", test, bitSize)
+		}
+	}
+}
+
+func (t *NumberSuite) TestPowersOfTen() {
+	mask := new(big.Int).SetUint64(math.MaxUint64)
+	pow := new(big.Int)
+	top := new(big.Int)
+	ten := big.NewInt(10)
+	for q := minExp10; q <= maxExp10; q++ {
+		if q < 0 {
+			// 2^k / 10^-q with k big enough to have at least 128 significant bits
+			pow.Exp(ten, big.NewInt(int64(-q)), nil)
+			top.Lsh(big.NewInt(1), uint(pow.BitLen()+127))
+			top.Quo(top, pow)
+		} else {
+			top.Exp(ten, big.NewInt(int64(q)), nil)
+		}
+		if shift := top.BitLen() - 128; shift > 0 {
+			top.Rsh(top, uint(shift))
+		} else {
+			top.Lsh(top, uint(-shift))
+		}
+		expected := [2]uint64{new(big.Int).And(top, mask).Uint64(), new(big.Int).Rsh(top, 64).Uint64()}
+		t.Require().Equal(expected, detailedPowersOfTen[q-minExp10], "1e%d", q)
+	}
+	// 1e43 = 0xE596B7B0_C643C719_6D9CCD05_D0000000 * 2^15
+	t.Equal([2]uint64{0x6D9CCD05D0000000, 0xE596B7B0C643C719}, detailedPowersOfTen[43-minExp10])
+	t.Equal([2]uint64{0, 1 << 63}, detailedPowersOfTen[-minExp10])
+	// 1e-1 = 0xCCCCCCCC_CCCCCCCC_CCCCCCCC_CCCCCCCC * 2^-131 rounded down
+	t.Equal([2]uint64{0xCCCCCCCCCCCCCCCC, 0xCCCCCCCCCCCCCCCC}, detailedPowersOfTen[-1-minExp10])
+}
+
+func (t *NumberSuite) TestParseFloatNoAllocs() {
+	data := []byte(`{"a": -1.7976931348623157e308, "b": "0.1", "c": 12345.6789e-3}`)
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = GetFloat[float64](data, "a")
+		_, _ = GetFloat[float32](data, "b")
+		_, _ = GetFloat[float64](data, "c")
+	})
+	t.Zero(allocs)
+}
+
+// telemetry is a float-heavy payload of sensor readings
+var telemetry = func() []byte {
+	rnd := rand.New(rand.NewSource(1))
+	var sb strings.Builder
+	sb.WriteString(`{"device": "sensor-17", "readings": [`)
+	for i := 0; i < 100; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, `{"ts": %d.%03d, "temp": %.2f, "lat": %.7f, "lon": %.7f, "gain": %e}`, 1700000000+i, rnd.Intn(1000),
+			rnd.Float64()*60-20, rnd.Float64()*180-90, rnd.Float64()*360-180, rnd.Float64()*1e-6)
+	}
+	sb.WriteString(`]}`)
+	return []byte(sb.String())
+}()
+
+func telemetryNumbers() [][]byte {
+	var ret [][]byte
+	lex := NewTokenizer(telemetry)
+	for {
+		lxm, err := lex.next()
+		if err != nil {
+			return ret
+		}
+		if lxm.Type == Float {
+			ret = append(ret, lxm.Value)
+		}
+	}
+}
+
+func BenchmarkParseFloat(b *testing.B) {
+	nums := telemetryNumbers()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, num := range nums {
+			_, _ = parseFloat(num, 64)
+		}
+	}
+}
+
+func BenchmarkParseFloatStrconv(b *testing.B) {
+	nums := telemetryNumbers()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, num := range nums {
+			_, _ = strconv.ParseFloat(string(num), 64)
+		}
+	}
+}
+
+func BenchmarkGetFloat(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = GetFloat[float64](telemetry, "readings", "[99]", "gain")
+	}
+}
@@ -1,93 +1,226 @@
 package jajson
 
 import (
-	"strconv"
 	"unsafe"
 )
 
+// Parser keeps options of parsing. Zero value parses strict JSON and is used by package level functions
+type Parser struct {
+	// GoEscapes is a lenient mode where strings are lexed and unquoted by Go string literal rules
+	// (\a, \v, \x41, \377, \U0001F600) instead of JSON ones
+	GoEscapes bool
+	// StrictSurrogates makes lone UTF-16 surrogates in \u escapes an error, by default they are replaced with U+FFFD
+	StrictSurrogates bool
+	// IntegralExponent allows GetInt and GetUInt to accept numbers with fraction or exponent (1e3, 2.50e1)
+	// as long as they represent an integer
+	IntegralExponent bool
+	// Comments treats // and /* */ comments as whitespace like JSONC files do, for example settings.json of VS Code
+	Comments bool
+	// TrailingCommas allows comma after the last member of object or element of array
+	TrailingCommas bool
+	// JSON5 parses JSON5: comments, trailing commas, single quoted strings, identifier keys, hexadecimal numbers,
+	// leading + sign, Infinity and NaN. Getters return normalized values, GoEscapes is ignored
+	JSON5 bool
+}
+
+var defaultParser = &Parser{}
+
 // GetRawValue returns part of the original slice with value
 func GetRawValue(data []byte, path ...string) (LexemeType, []byte, error) {
+	return defaultParser.GetRawValue(data, path...)
+}
+
+// IsNull reports whether the value is the null literal
+func IsNull(data []byte, path ...string) (bool, error) {
+	return defaultParser.IsNull(data, path...)
+}
+
+// GetString returns the unquoted string, ErrorNull is returned for null
+func GetString(data []byte, path ...string) (string, error) {
+	return defaultParser.GetString(data, path...)
+}
+
+func GetBool(data []byte, path ...string) (bool, error) {
+	return defaultParser.GetBool(data, path...)
+}
+
+func GetInt[T int | int8 | int16 | int32 | int64](data []byte, path ...string) (T, error) {
+	return getValue(defaultParser, data, path, toInt[T])
+}
+
+func GetUInt[T uint | uint8 | uint16 | uint32 | uint64](data []byte, path ...string) (T, error) {
+	return getValue(defaultParser, data, path, toUInt[T])
+}
+
+func GetFloat[T float32 | float64](data []byte, path ...string) (T, error) {
+	return getValue(defaultParser, data, path, toFloat[T])
+}
+
+// GetRawValue returns part of the original slice with value
+func (p *Parser) GetRawValue(data []byte, path ...string) (LexemeType, []byte, error) {
 	if len(data) == 0 {
 		return 0, nil, ErrorEmptyJSON
 	}
-	lex := newLexer(data)
+	lex := p.NewTokenizer(data)
 	if len(path) > 0 {
 		if err := skipPath(lex, path); err != nil {
-			return Err, nil, err
+			return Err, nil, withPath(err, formatPath(path))
 		}
 	}
-	return parseValue(lex)
+	typ, val, err := parseValue(lex)
+	if err != nil && len(path) > 0 {
+		return Err, nil, withPath(err, formatPath(path))
+	}
+	return typ, val, err
 }
 
-func GetString(data []byte, path ...string) (string, error) {
-	typ, val, err := GetRawValue(data, path...)
+// NewTokenizer returns tokenizer of data which follows options of the parser
+func (p *Parser) NewTokenizer(data []byte) *Tokenizer {
+	lex := NewTokenizer(data)
+	lex.goEscapes = p.GoEscapes && !p.JSON5
+	lex.strictSurrogates = p.StrictSurrogates
+	lex.json5 = p.JSON5
+	lex.comments = p.Comments || p.JSON5
+	lex.trailingCommas = p.TrailingCommas || p.JSON5
+	return lex
+}
+
+// unescape decodes string or key token according to the options
+func (p *Parser) unescape(val, buf []byte) ([]byte, error) {
+	return unescape(val, buf, p.GoEscapes && !p.JSON5, p.JSON5, p.StrictSurrogates)
+}
+
+// IsNull reports whether the value is the null literal
+func (p *Parser) IsNull(data []byte, path ...string) (bool, error) {
+	return getValue(p, data, path, (*Parser).toNull)
+}
+
+// GetString returns the unquoted string, ErrorNull is returned for null
+func (p *Parser) GetString(data []byte, path ...string) (string, error) {
+	return getValue(p, data, path, (*Parser).toString)
+}
+
+func (p *Parser) GetBool(data []byte, path ...string) (bool, error) {
+	return getValue(p, data, path, (*Parser).toBool)
+}
+
+func (p *Parser) GetInt(data []byte, path ...string) (int64, error) {
+	return getValue(p, data, path, toInt[int64])
+}
+
+func (p *Parser) GetUInt(data []byte, path ...string) (uint64, error) {
+	return getValue(p, data, path, toUInt[uint64])
+}
+
+func (p *Parser) GetFloat(data []byte, path ...string) (float64, error) {
+	return getValue(p, data, path, toFloat[float64])
+}
+
+// getValue converts the value found by path, errors of conversion are bound to the position of value and the path
+func getValue[T any](p *Parser, data []byte, path []string, conv func(*Parser, LexemeType, []byte) (T, error)) (T, error) {
+	typ, val, err := p.GetRawValue(data, path...)
 	if err != nil {
-		return "", err
+		var zero T
+		return zero, err
 	}
-	if typ != String {
-		return "", ErrorWrongValueType
+	ret, err := conv(p, typ, val)
+	if err != nil {
+		return ret, bindError(err, data, val, formatPath(path))
 	}
-	//TODO replace Unquote
-	return strconv.Unquote(string(val))
+	return ret, nil
 }
 
-func GetBool(data []byte, path ...string) (bool, error) {
-	typ, val, err := GetRawValue(data, path...)
-	if err != nil {
-		return false, err
+func (p *Parser) toNull(typ LexemeType, _ []byte) (bool, error) {
+	return typ == Null, nil
+}
+
+func (p *Parser) toString(typ LexemeType, val []byte) (string, error) {
+	if typ == Null {
+		return "", ErrorNull.withTypes(String, typ)
+	} else if typ != String {
+		return "", ErrorWrongValueType.withTypes(String, typ)
 	}
-	if typ != Bool {
-		return false, ErrorWrongValueType
+	val, err := p.unescape(val, nil)
+	return string(val), err
+}
+
+func (p *Parser) toBool(typ LexemeType, val []byte) (bool, error) {
+	if typ == Null {
+		return false, ErrorNull.withTypes(Bool, typ)
+	} else if typ != Bool {
+		return false, ErrorWrongValueType.withTypes(Bool, typ)
 	}
 	return val[0] == 't', nil
 }
 
-func GetInt[T int | int8 | int16 | int32 | int64](data []byte, path ...string) (T, error) {
+func toInt[T int | int8 | int16 | int32 | int64](p *Parser, typ LexemeType, val []byte) (T, error) {
 	var tmp T
 	size := int(unsafe.Sizeof(tmp)) * 8
-	return getNumber[T](data, func(bytes []byte) (T, error) {
-		//TODO replace ParseInt
-		ret, err := strconv.ParseInt(string(bytes), 10, size)
+	return toNumber[T](p, "", typ, val, func(bytes []byte) (T, error) {
+		ret, err := parseInt(bytes, size)
 		return T(ret), err
-	}, path...)
+	})
 }
 
-func GetUInt[T uint | uint8 | uint16 | uint32 | uint64](data []byte, path ...string) (T, error) {
+func toUInt[T uint | uint8 | uint16 | uint32 | uint64](p *Parser, typ LexemeType, val []byte) (T, error) {
 	var tmp T
 	size := int(unsafe.Sizeof(tmp)) * 8
-	return getNumber[T](data, func(bytes []byte) (T, error) {
-		//TODO replace ParseUInt
-		ret, err := strconv.ParseUint(string(bytes), 10, size)
+	return toNumber[T](p, "UInt", typ, val, func(bytes []byte) (T, error) {
+		ret, err := parseUint(bytes, size)
 		return T(ret), err
-	}, path...)
+	})
 }
 
-func getNumber[T int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64](data []byte, parse func([]byte) (T, error), path ...string) (T, error) {
-	typ, val, err := GetRawValue(data, path...)
-	if err != nil {
-		return 0, err
+func toNumber[T int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64](p *Parser, expectedName string, typ LexemeType, val []byte, parse func([]byte) (T, error)) (T, error) {
+	actual := typ
+	if typ == String {
+		val = val[1 : len(val)-1]
+		if p.IntegralExponent && isFloatSyntax(val) {
+			typ = Float
+		}
 	}
-	if typ == Int {
+	if p.JSON5 && len(val) > 1 && val[0] == '+' {
+		// JSON5 allows explicit plus sign, parseUint does not
+		val = val[1:]
+	}
+	if typ == Null {
+		return 0, ErrorNull.withNamedTypes(Int, expectedName, actual)
+	} else if p.JSON5 && (typ == Int || typ == String) && isHexSyntax(val) {
+		var buf [24]byte
+		digits, err := hexDigits(val, buf[:0])
+		if err != nil {
+			return 0, numberError(err)
+		}
+		return parse(digits)
+	} else if typ == Float && p.IntegralExponent {
+		var buf [32]byte
+		digits, err := integralDigits(val, buf[:0])
+		if err != nil {
+			return 0, numberError(err)
+		}
+		return parse(digits)
+	} else if typ == Int || typ == String {
 		return parse(val)
-	} else if typ == String {
-		return parse(val[1 : len(val)-1])
 	}
-	return 0, ErrorWrongValueType
+	return 0, ErrorWrongValueType.withNamedTypes(Int, expectedName, actual)
 }
 
-func GetFloat[T float32 | float64](data []byte, path ...string) (T, error) {
-	typ, val, err := GetRawValue(data, path...)
-	if err != nil {
-		return 0, err
-	}
+func toFloat[T float32 | float64](p *Parser, typ LexemeType, val []byte) (T, error) {
 	var tmp T
-	//TODO replace ParseFloat
-	if typ == Float {
-		ret, err := strconv.ParseFloat(string(val), int(unsafe.Sizeof(tmp))*8)
-		return T(ret), err
+	if typ == Null {
+		return 0, ErrorNull.withTypes(Float, typ)
 	} else if typ == String {
-		ret, err := strconv.ParseFloat(string(val[1:len(val)-1]), int(unsafe.Sizeof(tmp))*8)
-		return T(ret), err
+		val = val[1 : len(val)-1]
+	} else if typ != Float && typ != Int {
+		return 0, ErrorWrongValueType.withTypes(Float, typ)
+	}
+	if p.JSON5 {
+		var buf [24]byte
+		var err error
+		if val, err = normalizeJSON5Number(val, buf[:0]); err != nil {
+			return 0, numberError(err)
+		}
 	}
-	return 0, ErrorWrongValueType
+	ret, err := parseFloat(val, int(unsafe.Sizeof(tmp))*8)
+	return T(ret), err
 }
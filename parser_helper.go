@@ -2,37 +2,38 @@ package jajson
 
 import (
 	"bytes"
-	"strconv"
+	"unicode/utf8"
 )
 
-func parseValue(lex *lexer) (LexemeType, []byte, error) {
-	lxm, before, err := lex.nextToken()
+func parseValue(lex *Tokenizer) (LexemeType, []byte, error) {
+	lxm, err := lex.next()
 	if err != nil {
 		return Err, nil, err
 	}
-	if lxm.typ == String || lxm.typ == Bool || lxm.typ == Int || lxm.typ == Float {
-		return lxm.typ, lxm.value, nil
-	} else if lxm.typ == openCurve {
-		return parseObject(lex, before)
-	} else if lxm.typ == openBracket {
-		return parseArray(lex, before)
+	if lxm.Type == String || lxm.Type == Bool || lxm.Type == Int || lxm.Type == Float || lxm.Type == Null {
+		return lxm.Type, lxm.Value, nil
+	} else if lxm.Type == OpenCurve {
+		return parseObject(lex, lxm.BytePos)
+	} else if lxm.Type == OpenBracket {
+		return parseArray(lex, lxm.BytePos)
 	}
-	return Err, nil, ErrorUnexpectedLexeme.New(lxm.pos)
+	return Err, nil, lex.lexemeError(ErrorUnexpectedLexeme, lxm)
 }
 
-func parseObject(lex *lexer, before []byte) (LexemeType, []byte, error) {
+// parseObject parses the rest of object which starts at byte offset start
+func parseObject(lex *Tokenizer, start int) (LexemeType, []byte, error) {
 	//first bracket is skipped
-	lxm, data, err := lex.nextToken()
+	lxm, err := lex.next()
 	if err != nil {
 		return Err, nil, err
 	}
-	if lxm.typ == closeCurve {
-		return Object, before[:len(before)-len(data)+1], nil
-	} else if lxm.typ != String {
-		return Err, nil, ErrorUnexpectedLexeme.New(lxm.pos)
+	if lxm.Type == CloseCurve {
+		return Object, lex.slice(start, lxm.BytePos+1), nil
+	} else if !lex.isKey(lxm) {
+		return Err, nil, lex.lexemeError(ErrorUnexpectedLexeme, lxm)
 	}
 
-	if err := skipLexeme(lex, colon); err != nil {
+	if err := skipLexeme(lex, Colon); err != nil {
 		return Err, nil, err
 	}
 
@@ -40,21 +41,21 @@ func parseObject(lex *lexer, before []byte) (LexemeType, []byte, error) {
 	if err != nil {
 		return Err, nil, err
 	}
-	return skipObjectFields(lex, before)
+	return skipObjectFields(lex, start)
 }
 
-func skipObjectFields(lex *lexer, before []byte) (LexemeType, []byte, error) {
+func skipObjectFields(lex *Tokenizer, start int) (LexemeType, []byte, error) {
 	for {
-		typ, data, err := checkLexeme(lex, before, closeCurve, Object, Error{})
+		typ, data, err := checkLexeme(lex, start, CloseCurve, Object, Error{})
 		if typ != nothing {
 			return typ, data, err
 		}
 
-		if err := skipLexeme(lex, String); err != nil {
+		if err := skipKey(lex); err != nil {
 			return Err, nil, err
 		}
 
-		if err := skipLexeme(lex, colon); err != nil {
+		if err := skipLexeme(lex, Colon); err != nil {
 			return Err, nil, err
 		}
 
@@ -65,48 +66,83 @@ func skipObjectFields(lex *lexer, before []byte) (LexemeType, []byte, error) {
 	}
 }
 
-func checkLexeme(lex *lexer, before []byte, closeLexCheck, closeLexRet LexemeType, closeError Error) (LexemeType, []byte, error) {
-	lxm, data, err := lex.nextToken()
+func checkLexeme(lex *Tokenizer, start int, closeLexCheck, closeLexRet LexemeType, closeError Error) (LexemeType, []byte, error) {
+	lxm, err := lex.next()
 	if err != nil {
 		return Err, nil, err
 	}
-	if lxm.typ == closeLexCheck {
+	if lxm.Type == closeLexCheck {
 		if closeError.err != nil {
-			return Err, nil, closeError.New(lxm.pos)
+			return Err, nil, lex.lexemeError(closeError, lxm)
 		}
-		return closeLexRet, before[:len(before)-len(data)+1], nil
-	} else if lxm.typ != comma {
-		return Err, nil, ErrorUnexpectedLexeme.New(lxm.pos)
+		return closeLexRet, lex.slice(start, lxm.BytePos+1), nil
+	} else if lxm.Type != Comma {
+		return Err, nil, lex.lexemeError(ErrorUnexpectedLexeme, lxm)
 	}
-	return nothing, before, nil
+	if lex.isTrailingComma(closeLexCheck) {
+		return checkLexeme(lex, start, closeLexCheck, closeLexRet, closeError)
+	}
+	return nothing, nil, nil
+}
+
+// isTrailingComma reports whether the comma just skipped is followed by closeLex and it is allowed
+func (t *Tokenizer) isTrailingComma(closeLex LexemeType) bool {
+	if !t.trailingCommas {
+		return false
+	}
+	lxm, err := t.peek()
+	return err == nil && lxm.Type == closeLex
+}
+
+func skipLexeme(lex *Tokenizer, typ LexemeType) error {
+	lxm, err := lex.next()
+	if err != nil {
+		return err
+	}
+	if lxm.Type != typ {
+		return lex.lexemeError(ErrorUnexpectedLexeme, lxm)
+	}
+	return nil
 }
 
-func skipLexeme(lex *lexer, typ LexemeType) error {
-	lxm, _, err := lex.nextToken()
+func skipKey(lex *Tokenizer) error {
+	lxm, err := lex.next()
 	if err != nil {
 		return err
 	}
-	if lxm.typ != typ {
-		return ErrorUnexpectedLexeme.New(lxm.pos)
+	if !lex.isKey(lxm) {
+		return lex.lexemeError(ErrorUnexpectedLexeme, lxm)
 	}
 	return nil
 }
 
-func parseArray(lex *lexer, before []byte) (LexemeType, []byte, error) {
+// isKey reports whether lxm can be object key, JSON5 also allows identifier names including true, null and NaN
+func (t *Tokenizer) isKey(lxm Token) bool {
+	if lxm.Type == String {
+		return true
+	} else if !t.json5 || len(lxm.Value) == 0 {
+		return false
+	}
+	r, _ := utf8.DecodeRune(lxm.Value)
+	return isIdentifierStart(r)
+}
+
+// parseArray parses the rest of array which starts at byte offset start
+func parseArray(lex *Tokenizer, start int) (LexemeType, []byte, error) {
 	//first bracket is skipped
-	lxm, _, err := lex.lookup()
+	lxm, err := lex.peek()
 	if err != nil {
 		return Err, nil, err
 	}
-	if lxm.typ == closeBracket {
-		_, data, _ := lex.nextToken()
-		return Array, before[:len(before)-len(data)+1], nil
+	if lxm.Type == CloseBracket {
+		_, _ = lex.next()
+		return Array, lex.slice(start, lxm.BytePos+1), nil
 	}
 	if _, _, err = parseValue(lex); err != nil {
 		return Err, nil, err
 	}
 	for {
-		typ, data, err := checkLexeme(lex, before, closeBracket, Array, Error{})
+		typ, data, err := checkLexeme(lex, start, CloseBracket, Array, Error{})
 		if typ != nothing {
 			return typ, data, err
 		}
@@ -117,77 +153,279 @@ func parseArray(lex *lexer, before []byte) (LexemeType, []byte, error) {
 	}
 }
 
-func skipPath(lex *lexer, path []string) error {
+func skipPath(lex *Tokenizer, path []string) error {
 	for i := 0; i < len(path); i++ {
-		if err := skipPathPart(lex, []byte(path[i])); err != nil {
+		if err := skipPathPart(lex, path[i], pathIndex); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func skipPathPart(lex *lexer, path []byte) error {
-	if err := skipLexeme(lex, openCurve); err != nil {
+// skipPathPart enters object field or array element, index parses path part when array is encountered
+func skipPathPart(lex *Tokenizer, path string, index func(string) (int, bool)) error {
+	lxm, err := lex.next()
+	if err != nil {
 		return err
 	}
+	switch lxm.Type {
+	case OpenCurve:
+		return skipPathPartObject(lex, []byte(path))
+	case OpenBracket:
+		i, ok := index(path)
+		if !ok {
+			return lex.lexemeError(ErrorWrongPath.withTypes(Object, Array), lxm)
+		}
+		return skipPathPartArray(lex, i, lxm)
+	case String, Int, Float, Bool, Null:
+		expected := Object
+		if _, ok := index(path); ok {
+			expected = Array
+		}
+		return lex.lexemeError(ErrorWrongPath.withTypes(expected, lxm.Type), lxm)
+	}
+	return lex.lexemeError(ErrorUnexpectedLexeme, lxm)
+}
 
-	lxm, _, err := lex.nextToken()
+func skipPathPartObject(lex *Tokenizer, path []byte) error {
+	//first bracket is skipped
+	lxm, err := lex.next()
 	if err != nil {
 		return err
 	}
-	if lxm.typ != String {
-		return ErrorUnexpectedLexeme.New(lxm.pos)
+	if lxm.Type == CloseCurve {
+		return lex.lexemeError(ErrorWrongPath, lxm)
 	}
-	//TODO replace Unquote
-	field, err := strconv.Unquote(string(lxm.value))
-	if err != nil {
+	if found, err := matchKey(lex, lxm, path); err != nil || found {
 		return err
 	}
 
-	if err := skipLexeme(lex, colon); err != nil {
+	if _, _, err := parseValue(lex); err != nil {
 		return err
 	}
 
-	if bytes.Compare(path, []byte(field)) == 0 {
-		return nil
+	return skipPathPartFields(lex, path)
+}
+
+func skipPathPartArray(lex *Tokenizer, index int, open Token) error {
+	//first bracket is skipped
+	if index < 0 {
+		saved := lex.save(open.BytePos)
+		count, err := countElements(lex)
+		if err != nil {
+			return err
+		}
+		lex.rewind(saved)
+		index += count
+		if index < 0 {
+			return lex.lexemeError(ErrorWrongPath, open)
+		}
 	}
 
-	if _, _, err := parseValue(lex); err != nil {
+	lxm, err := lex.peek()
+	if err != nil {
 		return err
 	}
+	if lxm.Type == CloseBracket {
+		return lex.lexemeError(ErrorWrongPath, lxm)
+	}
+	for i := 0; i < index; i++ {
+		if _, _, err := parseValue(lex); err != nil {
+			return err
+		}
+		typ, _, err := checkLexeme(lex, 0, CloseBracket, Err, ErrorWrongPath)
+		if typ != nothing {
+			return err
+		}
+	}
+	return nil
+}
 
-	return skipPathPartFields(lex, path)
+// countElements skips the rest of array and returns amount of its elements
+func countElements(lex *Tokenizer) (int, error) {
+	lxm, err := lex.peek()
+	if err != nil {
+		return 0, err
+	}
+	if lxm.Type == CloseBracket {
+		return 0, nil
+	}
+	for count := 1; ; count++ {
+		if _, _, err := parseValue(lex); err != nil {
+			return 0, err
+		}
+		if closed, err := skipSeparator(lex, CloseBracket); err != nil || closed {
+			return count, err
+		}
+	}
 }
 
-func skipPathPartFields(lex *lexer, path []byte) error {
+// pathIndex parses path part in the form of [N] or [-N], negative N counts from the end of array
+func pathIndex(path string) (int, bool) {
+	if len(path) < 3 || path[0] != '[' || path[len(path)-1] != ']' {
+		return 0, false
+	}
+	path = path[1 : len(path)-1]
+	neg := path[0] == '-'
+	if neg {
+		path = path[1:]
+	}
+	if len(path) == 0 || len(path) > 18 {
+		return 0, false
+	}
+	index := 0
+	for i := 0; i < len(path); i++ {
+		if !isDigit(path[i]) {
+			return 0, false
+		}
+		index = index*10 + int(path[i]-'0')
+	}
+	if neg {
+		return -index, true
+	}
+	return index, true
+}
+
+func skipPathPartFields(lex *Tokenizer, path []byte) error {
 	for {
-		typ, _, err := checkLexeme(lex, nil, closeCurve, Err, ErrorWrongPath)
+		typ, _, err := checkLexeme(lex, 0, CloseCurve, Err, ErrorWrongPath)
 		if typ != nothing {
 			return err
 		}
 
-		lxm, _, err := lex.nextToken()
+		lxm, err := lex.next()
 		if err != nil {
 			return err
-		} else if lxm.typ != String {
-			return ErrorUnexpectedLexeme.New(lxm.pos)
 		}
-		//TODO replace Unquote
-		field, err := strconv.Unquote(string(lxm.value))
-		if err != nil {
+		if found, err := matchKey(lex, lxm, path); err != nil || found {
 			return err
 		}
 
-		if err := skipLexeme(lex, colon); err != nil {
+		if _, _, err := parseValue(lex); err != nil {
 			return err
 		}
+	}
+}
+
+// matchKey checks that lxm is a key equal to path and skips colon after it
+func matchKey(lex *Tokenizer, lxm Token, path []byte) (bool, error) {
+	if !lex.isKey(lxm) {
+		return false, lex.lexemeError(ErrorUnexpectedLexeme, lxm)
+	}
+	var buf [64]byte
+	field, err := unescape(lxm.Value, buf[:0], lex.goEscapes, lex.json5, lex.strictSurrogates)
+	if err != nil {
+		return false, lex.tokenError(err, lxm)
+	}
+
+	if err := skipLexeme(lex, Colon); err != nil {
+		return false, err
+	}
+	return bytes.Equal(path, field), nil
+}
+
+// skipSeparator skips comma or closing token and reports whether the container was closed
+func skipSeparator(lex *Tokenizer, closeLex LexemeType) (bool, error) {
+	_, closed, err := separator(lex, closeLex)
+	return closed, err
+}
+
+// separator is skipSeparator which also returns the closing token
+func separator(lex *Tokenizer, closeLex LexemeType) (Token, bool, error) {
+	lxm, err := lex.next()
+	if err != nil {
+		return lxm, false, err
+	}
+	if lxm.Type == closeLex {
+		return lxm, true, nil
+	} else if lxm.Type != Comma {
+		return lxm, false, lex.lexemeError(ErrorUnexpectedLexeme, lxm)
+	}
+	if lex.isTrailingComma(closeLex) {
+		lxm, err = lex.next()
+		return lxm, true, err
+	}
+	return lxm, false, nil
+}
+
+type child struct {
+	// key is the raw key token with quotes or JSON5 identifier, it is nil for array elements
+	key   []byte
+	typ   LexemeType
+	value []byte
+}
+
+// parseChildren parses the value and returns members of object or elements of array
+func parseChildren(lex *Tokenizer) (LexemeType, []child, error) {
+	lxm, err := lex.next()
+	if err != nil {
+		return Err, nil, err
+	}
+	switch lxm.Type {
+	case String, Int, Float, Bool, Null:
+		return lxm.Type, nil, nil
+	case OpenCurve:
+		children, err := parseMembers(lex)
+		return Object, children, err
+	case OpenBracket:
+		children, err := parseElements(lex)
+		return Array, children, err
+	}
+	return Err, nil, lex.lexemeError(ErrorUnexpectedLexeme, lxm)
+}
+
+func parseMembers(lex *Tokenizer) ([]child, error) {
+	//first bracket is skipped
+	var children []child
+	lxm, err := lex.next()
+	if err != nil {
+		return nil, err
+	}
+	if lxm.Type == CloseCurve {
+		return children, nil
+	}
+	for {
+		if !lex.isKey(lxm) {
+			return nil, lex.lexemeError(ErrorUnexpectedLexeme, lxm)
+		}
+		if err := skipLexeme(lex, Colon); err != nil {
+			return nil, err
+		}
+		typ, val, err := parseValue(lex)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child{key: lxm.Value, typ: typ, value: val})
 
-		if bytes.Compare(path, []byte(field)) == 0 {
-			return nil
+		if closed, err := skipSeparator(lex, CloseCurve); err != nil || closed {
+			return children, err
 		}
+		if lxm, err = lex.next(); err != nil {
+			return nil, err
+		}
+	}
+}
 
-		if _, _, err := parseValue(lex); err != nil {
-			return err
+func parseElements(lex *Tokenizer) ([]child, error) {
+	//first bracket is skipped
+	var children []child
+	lxm, err := lex.peek()
+	if err != nil {
+		return nil, err
+	}
+	if lxm.Type == CloseBracket {
+		_, err = lex.next()
+		return children, err
+	}
+	for {
+		typ, val, err := parseValue(lex)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child{typ: typ, value: val})
+
+		if closed, err := skipSeparator(lex, CloseBracket); err != nil || closed {
+			return children, err
 		}
 	}
 }
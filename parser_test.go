@@ -3,6 +3,7 @@ package jajson_test
 import (
 	"github.com/aleksandrzhukovskii/jajson"
 	"github.com/stretchr/testify/suite"
+	"math"
 	"testing"
 )
 
@@ -237,3 +238,296 @@ func (t *ParserSuite) TestSkipPath() {
 	t.NoError(err)
 	t.True(val)
 }
+
+func (t *ParserSuite) TestParseNull() {
+	str := []byte(`{"a": null, "b": [null, {"c": null}], "d": "value"}`)
+	val, err := jajson.GetString(str, "d")
+	t.NoError(err)
+	t.Equal("value", val)
+
+	isNull, err := jajson.IsNull(str, "a")
+	t.NoError(err)
+	t.True(isNull)
+
+	isNull, err = jajson.IsNull(str, "d")
+	t.NoError(err)
+	t.False(isNull)
+
+	_, err = jajson.IsNull(str, "e")
+	t.ErrorIs(err, jajson.ErrorWrongPath)
+
+	_, err = jajson.GetString(str, "a")
+	t.ErrorIs(err, jajson.ErrorNull)
+	_, err = jajson.GetBool(str, "a")
+	t.ErrorIs(err, jajson.ErrorNull)
+	_, err = jajson.GetInt[int](str, "a")
+	t.ErrorIs(err, jajson.ErrorNull)
+	_, err = jajson.GetFloat[float64](str, "a")
+	t.ErrorIs(err, jajson.ErrorNull)
+	_, err = jajson.GetString(str, "b")
+	t.ErrorIs(err, jajson.ErrorWrongValueType)
+
+	typ, raw, err := jajson.GetRawValue(str, "b")
+	t.NoError(err)
+	t.Equal(jajson.Array, typ)
+	t.Equal([]byte(`[null, {"c": null}]`), raw)
+}
+
+func (t *ParserSuite) TestParseExponent() {
+	val, err := jajson.GetFloat[float64]([]byte(`{"a": -2.5E-3}`), "a")
+	t.NoError(err)
+	t.Equal(-2.5e-3, val)
+
+	val, err = jajson.GetFloat[float64]([]byte(`6.02e+23`))
+	t.NoError(err)
+	t.Equal(6.02e+23, val)
+
+	val, err = jajson.GetFloat[float64]([]byte(`42`))
+	t.NoError(err)
+	t.Equal(42.0, val)
+
+	_, err = jajson.GetInt[int]([]byte(`1e3`))
+	t.ErrorIs(err, jajson.ErrorWrongValueType)
+
+	parser := jajson.Parser{IntegralExponent: true}
+	tests := []struct {
+		data     string
+		expected int64
+	}{
+		{data: `1e3`, expected: 1000},
+		{data: `2.50e1`, expected: 25},
+		{data: `-1.0`, expected: -1},
+		{data: `0.0e5`, expected: 0},
+		{data: `"1E2"`, expected: 100},
+		{data: `9223372036854775807`, expected: 9223372036854775807},
+	}
+	for _, test := range tests {
+		val, err := parser.GetInt([]byte(test.data))
+		t.NoError(err, test.data)
+		t.Equal(test.expected, val, test.data)
+	}
+
+	_, err = parser.GetInt([]byte(`12e-1`))
+	t.ErrorIs(err, jajson.ErrorWrongValueType)
+	_, err = parser.GetInt([]byte(`1e19`))
+	t.Error(err)
+	uval, err := parser.GetUInt([]byte(`1e19`))
+	t.NoError(err)
+	t.Equal(uint64(1e19), uval)
+	_, err = parser.GetUInt([]byte(`1e400`))
+	t.Error(err)
+}
+
+func (t *ParserSuite) TestParseStringEscapes() {
+	val, err := jajson.GetString([]byte(`{"a\/b": "\"\\\/\b\f\n\r\t\u0041"}`), "a/b")
+	t.NoError(err)
+	t.Equal("\"\\/\b\f\n\r\tA", val)
+
+	_, err = jajson.GetString([]byte(`"\x41"`))
+	t.Error(err)
+
+	parser := jajson.Parser{GoEscapes: true}
+	val, err = parser.GetString([]byte(`"\x41\101\U0001F600\a"`))
+	t.NoError(err)
+	t.Equal("AA\U0001F600\a", val)
+}
+
+func (t *ParserSuite) TestParseStringSurrogates() {
+	str := []byte(`{"\uD83D\uDE00": "\uD83D\uDE00", "lone": "\uD83D"}`)
+	val, err := jajson.GetString(str, "\U0001F600")
+	t.NoError(err)
+	t.Equal("\U0001F600", val)
+
+	val, err = jajson.GetString(str, "lone")
+	t.NoError(err)
+	t.Equal("\uFFFD", val)
+
+	parser := jajson.Parser{StrictSurrogates: true}
+	_, err = parser.GetString(str, "lone")
+	t.ErrorIs(err, jajson.ErrorLoneSurrogate)
+}
+
+func (t *ParserSuite) TestSkipPathIndex() {
+	str := []byte(`{"items": [{"id": 7}, {"id": 8, "tags": ["a", "b", "c"]}, 9], "empty": [], "obj": {"[0]": 1}}`)
+	id, err := jajson.GetInt[int](str, "items", "[0]", "id")
+	t.NoError(err)
+	t.Equal(7, id)
+
+	id, err = jajson.GetInt[int](str, "items", "[1]", "id")
+	t.NoError(err)
+	t.Equal(8, id)
+
+	id, err = jajson.GetInt[int](str, "items", "[-1]")
+	t.NoError(err)
+	t.Equal(9, id)
+
+	tag, err := jajson.GetString(str, "items", "[-2]", "tags", "[-3]")
+	t.NoError(err)
+	t.Equal("a", tag)
+
+	id, err = jajson.GetInt[int](str, "obj", "[0]")
+	t.NoError(err)
+	t.Equal(1, id)
+
+	typ, raw, err := jajson.GetRawValue(str, "items", "[1]", "tags")
+	t.NoError(err)
+	t.Equal(jajson.Array, typ)
+	t.Equal([]byte(`["a", "b", "c"]`), raw)
+
+	for _, path := range [][]string{{"items", "[3]"}, {"items", "[-4]"}, {"empty", "[0]"}, {"empty", "[-1]"}, {"items", "0"}, {"items", "[x]"}, {"obj", "missing"}} {
+		_, _, err = jajson.GetRawValue(str, path...)
+		t.ErrorContains(err, "path not found", path)
+	}
+}
+
+func (t *ParserSuite) TestParseIntOverflow() {
+	str := []byte(`{"values": [127, 128, -129, 65536, "4294967296"]}`)
+	_, err := jajson.GetInt[int8](str, "values", "[1]")
+	t.ErrorIs(err, jajson.ErrorNumberRange)
+	var e jajson.Error
+	t.Require().ErrorAs(err, &e)
+	t.Equal("values[1]", e.Path())
+	t.Equal("128", e.Literal())
+
+	val, err := jajson.GetInt[int8](str, "values", "[0]")
+	t.NoError(err)
+	t.Equal(int8(127), val)
+
+	_, err = jajson.GetInt[int8](str, "values", "[2]")
+	t.ErrorIs(err, jajson.ErrorNumberRange)
+	_, err = jajson.GetUInt[uint16](str, "values", "[3]")
+	t.ErrorIs(err, jajson.ErrorNumberRange)
+	_, err = jajson.GetUInt[uint32](str, "values", "[4]")
+	t.ErrorIs(err, jajson.ErrorNumberRange)
+	t.Require().ErrorAs(err, &e)
+	t.Equal("4294967296", e.Literal())
+	_, err = jajson.GetUInt[uint](str, "values", "[2]")
+	t.ErrorIs(err, jajson.ErrorNumberSyntax)
+}
+
+func (t *ParserSuite) TestJSON5() {
+	str := []byte(`// settings
+{
+	name: 'jajson', /* inline */ "version": +2,
+	$id: 0xFF, neg: -0x10, ratio: .5, half: 5., big: +1e3,
+	inf: -Infinity, nan: NaN, plus: +NaN,
+	text: 'it\'s "quoted"\x41\
+next line',
+	list: [1, 2, 3,],
+	true: null,
+	nested: {ключ: 'v',},
+}`)
+	parser := jajson.Parser{JSON5: true}
+	s, err := parser.GetString(str, "name")
+	t.NoError(err)
+	t.Equal("jajson", s)
+
+	s, err = parser.GetString(str, "text")
+	t.NoError(err)
+	t.Equal(`it's "quoted"Anext line`, s)
+
+	s, err = parser.GetString(str, "nested", "ключ")
+	t.NoError(err)
+	t.Equal("v", s)
+
+	i, err := parser.GetInt(str, "version")
+	t.NoError(err)
+	t.Equal(int64(2), i)
+
+	i, err = parser.GetInt(str, "$id")
+	t.NoError(err)
+	t.Equal(int64(255), i)
+
+	i, err = parser.GetInt(str, "neg")
+	t.NoError(err)
+	t.Equal(int64(-16), i)
+
+	for path, expected := range map[string]uint64{"version": 2, "$id": 255} {
+		u, err := parser.GetUInt(str, path)
+		t.NoError(err, path)
+		t.Equal(expected, u, path)
+	}
+	u, err := parser.GetUInt([]byte(`+0x1F`))
+	t.NoError(err)
+	t.Equal(uint64(31), u)
+	_, err = parser.GetUInt(str, "neg")
+	t.ErrorIs(err, jajson.ErrorNumberSyntax)
+
+	for path, expected := range map[string]float64{"ratio": 0.5, "half": 5, "big": 1000, "inf": math.Inf(-1), "$id": 255} {
+		f, err := parser.GetFloat(str, path)
+		t.NoError(err, path)
+		t.Equal(expected, f, path)
+	}
+	for _, path := range []string{"nan", "plus"} {
+		f, err := parser.GetFloat(str, path)
+		t.NoError(err, path)
+		t.True(math.IsNaN(f), path)
+	}
+
+	i, err = parser.GetInt(str, "list", "[2]")
+	t.NoError(err)
+	t.Equal(int64(3), i)
+
+	null, err := parser.IsNull(str, "true")
+	t.NoError(err)
+	t.True(null)
+
+	typ, val, err := parser.GetRawValue(str, "list")
+	t.NoError(err)
+	t.Equal(jajson.Array, typ)
+	t.Equal("[1, 2, 3,]", string(val))
+
+	_, _, err = parser.GetRawValue(str, "list", "[3]")
+	t.ErrorIs(err, jajson.ErrorWrongPath)
+
+	t.NoError(parser.Validate(str))
+	t.Error(jajson.Validate(str))
+}
+
+func (t *ParserSuite) TestJSON5Invalid() {
+	parser := jajson.Parser{JSON5: true}
+	for _, test := range []string{`{a: b}`, `[1,,]`, `[,]`, `{,}`, `01`, `0x`, `+`, `'\1'`, "'a\nb'", `/* open`, `{1: 2}`, `.e1`, `+Inf`} {
+		t.Error(parser.Validate([]byte(test)), test)
+	}
+}
+
+func (t *ParserSuite) TestComments() {
+	str := []byte("{\r\n" +
+		"\t// Compiler options\r\n" +
+		"\t\"compilerOptions\": {\n" +
+		"\t\t\"target\": /* ☺ */ \"es2020\", // trailing\n" +
+		"\t\t\"strict\": true,\n" +
+		"\t\t/* multi\n" +
+		"\t\t   line */\n" +
+		"\t\t\"outDir\": \"dist\",\n" +
+		"\t},\n" +
+		"}\n" +
+		"// end")
+	_, err := jajson.GetString(str, "compilerOptions", "target")
+	t.ErrorIs(err, jajson.ErrorUnexpected)
+
+	parser := jajson.Parser{Comments: true}
+	val, err := parser.GetString(str, "compilerOptions", "target")
+	t.NoError(err)
+	t.Equal("es2020", val)
+	t.Error(parser.Validate(str))
+
+	_, err = parser.GetBool(str, "compilerOptions", "outDir")
+	var e jajson.Error
+	t.Require().ErrorAs(err, &e)
+	t.ErrorIs(err, jajson.ErrorWrongValueType)
+	t.Equal(8, e.Line())
+	t.Equal(13, e.Column())
+
+	parser.TrailingCommas = true
+	t.NoError(parser.Validate(str))
+	val, err = parser.GetString(str, "compilerOptions", "outDir")
+	t.NoError(err)
+	t.Equal("dist", val)
+
+	err = parser.Validate([]byte("{\"a\": 1 /* open"))
+	t.Require().ErrorAs(err, &e)
+	t.ErrorIs(err, jajson.ErrorUnexpected)
+	t.Equal(8, e.Pos())
+	t.Error(parser.Validate([]byte("[1 / 2]")))
+}
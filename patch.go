@@ -0,0 +1,385 @@
+package jajson
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PatchError is returned by ApplyPatch when an operation fails, Err is the cause
+type PatchError struct {
+	// Index is the index of the failed operation in the patch
+	Index int
+	Op    string
+	Err   error
+}
+
+func (e PatchError) Error() string {
+	return fmt.Sprintf("Operation: %d. Op: %s. %s", e.Index, e.Op, e.Err.Error())
+}
+
+// Unwrap returns the cause, so errors.Is(err, ErrorPatchTest) works for PatchError
+func (e PatchError) Unwrap() error {
+	return e.Err
+}
+
+type patchOperation struct {
+	op, path, from string
+	typ            LexemeType
+	value          []byte
+}
+
+// ApplyPatch returns copy of data with RFC 6902 JSON patch applied
+func ApplyPatch(data, patch []byte) ([]byte, error) {
+	return defaultParser.ApplyPatch(data, patch)
+}
+
+// CreatePatch returns RFC 6902 JSON patch which turns original into modified
+func CreatePatch(original, modified []byte) ([]byte, error) {
+	return defaultParser.CreatePatch(original, modified)
+}
+
+// ApplyPatch returns copy of data with RFC 6902 JSON patch applied, untouched bytes are preserved.
+// Operations are applied atomically: when one of them fails PatchError with its index is returned
+// and no result is produced
+func (p *Parser) ApplyPatch(data, patch []byte) ([]byte, error) {
+	if err := p.Validate(data); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(patch); err != nil {
+		return nil, err
+	}
+	ops, err := p.parsePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	ret := append([]byte(nil), data...)
+	for i, op := range ops {
+		if ret, err = p.applyOperation(ret, op); err != nil {
+			return nil, PatchError{Index: i, Op: op.op, Err: err}
+		}
+	}
+	return ret, nil
+}
+
+// parsePatch reads operations of valid patch
+func (p *Parser) parsePatch(patch []byte) ([]patchOperation, error) {
+	var ops []patchOperation
+	var op patchOperation
+	failed := false
+	err := p.ArrayEach(patch, func(_ int, typ LexemeType, value []byte) error {
+		op = patchOperation{}
+		err := p.readOperation(&op, typ, value)
+		if err != nil {
+			failed = true
+			return err
+		}
+		ops = append(ops, op)
+		return nil
+	})
+	if failed {
+		return nil, PatchError{Index: len(ops), Op: op.op, Err: err}
+	}
+	return ops, err
+}
+
+// readOperation reads operation object, errors are positioned in value
+func (p *Parser) readOperation(op *patchOperation, typ LexemeType, value []byte) error {
+	if typ != Object {
+		return bindError(ErrorPatchOperation.withTypes(Object, typ), value, value, "")
+	}
+	var err error
+	if op.op, err = p.GetString(value, "op"); err != nil {
+		return err
+	}
+	if op.path, err = p.GetString(value, "path"); err != nil {
+		return err
+	}
+	switch op.op {
+	case "add", "replace", "test":
+		op.typ, op.value, err = p.GetRawValue(value, "value")
+	case "move", "copy":
+		op.from, err = p.GetString(value, "from")
+	case "remove":
+	default:
+		err = bindError(ErrorPatchOperation, value, value, "op")
+	}
+	return err
+}
+
+func (p *Parser) applyOperation(data []byte, op patchOperation) ([]byte, error) {
+	switch op.op {
+	case "add":
+		return p.patchAdd(data, op.path, op.value)
+	case "remove":
+		return p.patchRemove(data, op.path)
+	case "replace":
+		return p.patchReplace(data, op.path, op.value)
+	case "move":
+		// from must exist even when the value is moved to itself
+		_, val, err := p.GetRawValueByPointer(data, op.from)
+		if err != nil {
+			return nil, err
+		} else if op.path == op.from {
+			return data, nil
+		} else if strings.HasPrefix(op.path, op.from+"/") {
+			return nil, withPath(ErrorPatchOperation, op.path)
+		}
+		ret, err := p.patchRemove(data, op.from)
+		if err != nil {
+			return nil, err
+		}
+		return p.patchAdd(ret, op.path, val)
+	case "copy":
+		_, val, err := p.GetRawValueByPointer(data, op.from)
+		if err != nil {
+			return nil, err
+		}
+		return p.patchAdd(data, op.path, val)
+	}
+	typ, val, err := p.GetRawValueByPointer(data, op.path)
+	if err != nil {
+		return nil, err
+	}
+	if equal, err := p.equalValues(typ, val, op.typ, op.value); err != nil {
+		return nil, err
+	} else if !equal {
+		return nil, bindError(ErrorPatchTest, data, val, op.path)
+	}
+	return data, nil
+}
+
+// patchAdd adds member to object or inserts element into array, "-" token appends element
+func (p *Parser) patchAdd(data []byte, pointer string, value []byte) ([]byte, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	} else if len(tokens) == 0 {
+		return append([]byte(nil), value...), nil
+	}
+	ret, err := p.addByTokens(data, tokens, value)
+	if err != nil {
+		return nil, withPath(err, pointer)
+	}
+	return ret, nil
+}
+
+func (p *Parser) addByTokens(data []byte, tokens []string, value []byte) ([]byte, error) {
+	lex := p.NewTokenizer(data)
+	for _, token := range tokens[:len(tokens)-1] {
+		if err := skipPathPart(lex, token, pointerIndex); err != nil {
+			return nil, err
+		}
+	}
+	last := tokens[len(tokens)-1]
+	lxm, err := lex.peek()
+	if err != nil {
+		return nil, err
+	}
+	switch lxm.Type {
+	case OpenCurve:
+		loc, err := locate(lex, tokens[len(tokens)-1:], pointerIndex)
+		if err != nil {
+			return nil, err
+		} else if loc.missing >= 0 {
+			return addMember(data, loc, []byte(last), value), nil
+		}
+		if lxm, err = lex.peek(); err != nil {
+			return nil, err
+		}
+		_, val, err := parseValue(lex)
+		if err != nil {
+			return nil, err
+		}
+		return splice(data, lxm.BytePos, lxm.BytePos+len(val), value), nil
+	case OpenBracket:
+		if last == "-" {
+			return insertElement(lex, data, value, math.MaxInt, true)
+		}
+		index, ok := pointerIndex(last)
+		if !ok {
+			return nil, lex.lexemeError(ErrorWrongPath.withTypes(Object, Array), lxm)
+		}
+		return insertElement(lex, data, value, index, false)
+	case String, Int, Float, Bool, Null:
+		return nil, lex.lexemeError(ErrorWrongPath.withTypes(Object, lxm.Type), lxm)
+	}
+	return nil, lex.lexemeError(ErrorUnexpectedLexeme, lxm)
+}
+
+// patchRemove removes member or element, the whole document cannot be removed
+func (p *Parser) patchRemove(data []byte, pointer string) ([]byte, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	} else if len(tokens) == 0 {
+		return nil, withPath(ErrorWrongPath, pointer)
+	}
+	lex := p.NewTokenizer(data)
+	for _, token := range tokens[:len(tokens)-1] {
+		if err := skipPathPart(lex, token, pointerIndex); err != nil {
+			return nil, withPath(err, pointer)
+		}
+	}
+	span, err := findChild(lex, tokens[len(tokens)-1], pointerIndex)
+	if err != nil {
+		return nil, withPath(err, pointer)
+	}
+	return removeChild(data, span), nil
+}
+
+// patchReplace replaces existing value
+func (p *Parser) patchReplace(data []byte, pointer string, value []byte) ([]byte, error) {
+	if pointer == "" {
+		return append([]byte(nil), value...), nil
+	}
+	_, val, err := p.GetRawValueByPointer(data, pointer)
+	if err != nil {
+		return nil, err
+	}
+	start := cap(data) - cap(val)
+	return splice(data, start, start+len(val), value), nil
+}
+
+// CreatePatch returns RFC 6902 JSON patch which turns original into modified. Object members are
+// compared by key, array elements by index: extra elements are added or removed at the end of array.
+// Values are compared like in filters of JSONPath, so 1 and 1.0 are equal
+func (p *Parser) CreatePatch(original, modified []byte) ([]byte, error) {
+	if err := p.Validate(original); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(modified); err != nil {
+		return nil, err
+	}
+	typA, a, _ := p.GetRawValue(original)
+	typB, b, _ := p.GetRawValue(modified)
+	w := patchWriter{p: p, buf: []byte{'['}}
+	if err := w.diff("", typA, a, typB, b); err != nil {
+		return nil, err
+	}
+	return append(w.buf, ']'), nil
+}
+
+type patchWriter struct {
+	p   *Parser
+	buf []byte
+}
+
+// operation appends operation to the patch, value is omitted when it is nil
+func (w *patchWriter) operation(op, pointer string, value []byte) {
+	if len(w.buf) > 1 {
+		w.buf = append(w.buf, ',')
+	}
+	w.buf = append(w.buf, `{"op":"`...)
+	w.buf = append(w.buf, op...)
+	w.buf = append(w.buf, `","path":`...)
+	w.buf = appendQuote(w.buf, []byte(pointer))
+	if value != nil {
+		w.buf = append(w.buf, `,"value":`...)
+		w.buf = append(w.buf, value...)
+	}
+	w.buf = append(w.buf, '}')
+}
+
+func (w *patchWriter) diff(pointer string, typA LexemeType, a []byte, typB LexemeType, b []byte) error {
+	if typA == Object && typB == Object {
+		return w.diffObjects(pointer, a, b)
+	} else if typA == Array && typB == Array {
+		return w.diffArrays(pointer, a, b)
+	}
+	equal, err := w.p.equalValues(typA, a, typB, b)
+	if err == nil && !equal {
+		w.operation("replace", pointer, b)
+	}
+	return err
+}
+
+func (w *patchWriter) diffObjects(pointer string, a, b []byte) error {
+	_, membersA, err := parseChildren(w.p.NewTokenizer(a))
+	if err != nil {
+		return err
+	}
+	_, membersB, err := parseChildren(w.p.NewTokenizer(b))
+	if err != nil {
+		return err
+	}
+	keysA, err := w.keys(membersA)
+	if err != nil {
+		return err
+	}
+	keysB, err := w.keys(membersB)
+	if err != nil {
+		return err
+	}
+	modified := make(map[string]child, len(membersB))
+	for i, member := range membersB {
+		modified[keysB[i]] = member
+	}
+	original := make(map[string]bool, len(membersA))
+	for i, member := range membersA {
+		key := keysA[i]
+		if original[key] {
+			continue
+		}
+		original[key] = true
+		if m, ok := modified[key]; !ok {
+			w.operation("remove", pointer+"/"+escapePointerToken(key), nil)
+		} else if err := w.diff(pointer+"/"+escapePointerToken(key), member.typ, member.value, m.typ, m.value); err != nil {
+			return err
+		}
+	}
+	for i, member := range membersB {
+		if key := keysB[i]; !original[key] {
+			original[key] = true
+			w.operation("add", pointer+"/"+escapePointerToken(key), member.value)
+		}
+	}
+	return nil
+}
+
+// keys returns decoded keys of members
+func (w *patchWriter) keys(members []child) ([]string, error) {
+	keys := make([]string, len(members))
+	for i, member := range members {
+		key, err := w.p.unescape(member.key, nil)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = string(key)
+	}
+	return keys, nil
+}
+
+func (w *patchWriter) diffArrays(pointer string, a, b []byte) error {
+	_, elementsA, err := parseChildren(w.p.NewTokenizer(a))
+	if err != nil {
+		return err
+	}
+	_, elementsB, err := parseChildren(w.p.NewTokenizer(b))
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(elementsA) && i < len(elementsB); i++ {
+		x, y := elementsA[i], elementsB[i]
+		if err := w.diff(pointer+"/"+strconv.Itoa(i), x.typ, x.value, y.typ, y.value); err != nil {
+			return err
+		}
+	}
+	for i := len(elementsA); i < len(elementsB); i++ {
+		w.operation("add", pointer+"/"+strconv.Itoa(i), elementsB[i].value)
+	}
+	// elements are removed from the end, so indices of the rest do not change
+	for i := len(elementsA) - 1; i >= len(elementsB); i-- {
+		w.operation("remove", pointer+"/"+strconv.Itoa(i), nil)
+	}
+	return nil
+}
+
+// escapePointerToken escapes ~ and / of JSON pointer reference token
+func escapePointerToken(token string) string {
+	if strings.IndexAny(token, "~/") < 0 {
+		return token
+	}
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
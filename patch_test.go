@@ -0,0 +1,131 @@
+package jajson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aleksandrzhukovskii/jajson"
+	"github.com/stretchr/testify/suite"
+)
+
+type PatchSuite struct {
+	suite.Suite
+}
+
+func TestPatch(t *testing.T) {
+	suite.Run(t, new(PatchSuite))
+}
+
+func (t *PatchSuite) TestApplyPatch() {
+	// examples of RFC 6902 Appendix A
+	tests := []struct {
+		data, patch, expected string
+	}{
+		{data: `{"foo": "bar"}`, patch: `[{"op": "add", "path": "/baz", "value": "qux"}]`, expected: `{"foo": "bar","baz":"qux"}`},
+		{data: `{"foo": ["bar", "baz"]}`, patch: `[{"op": "add", "path": "/foo/1", "value": "qux"}]`, expected: `{"foo": ["bar", "qux", "baz"]}`},
+		{data: `{"baz": "qux", "foo": "bar"}`, patch: `[{"op": "remove", "path": "/baz"}]`, expected: `{"foo": "bar"}`},
+		{data: `{"foo": ["bar", "qux", "baz"]}`, patch: `[{"op": "remove", "path": "/foo/1"}]`, expected: `{"foo": ["bar", "baz"]}`},
+		{data: `{"baz": "qux", "foo": "bar"}`, patch: `[{"op": "replace", "path": "/baz", "value": "boo"}]`, expected: `{"baz": "boo", "foo": "bar"}`},
+		{
+			data:     `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch:    `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			expected: `{"foo": {"bar": "baz"}, "qux": {"corge": "grault","thud":"fred"}}`,
+		},
+		{data: `{"foo": ["all", "grass", "cows", "eat"]}`, patch: `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`, expected: `{"foo": ["all", "cows", "eat", "grass"]}`},
+		{
+			data:     `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			patch:    `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2.0}]`,
+			expected: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		{data: `{"foo": "bar"}`, patch: `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`, expected: `{"foo": "bar","child":{"grandchild": {}}}`},
+		{data: `{"foo": ["bar"]}`, patch: `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`, expected: `{"foo": ["bar",["abc", "def"]]}`},
+		{data: `{"foo": null}`, patch: `[{"op": "test", "path": "/foo", "value": null}]`, expected: `{"foo": null}`},
+		{data: `{"/": 9, "~1": 10}`, patch: `[{"op": "test", "path": "/~01", "value": 10}]`, expected: `{"/": 9, "~1": 10}`},
+		{data: `{"foo": "bar"}`, patch: `[{"op": "copy", "from": "/foo", "path": "/baz"}, {"op": "replace", "path": "", "value": [1]}]`, expected: `[1]`},
+		{data: `{"foo": []}`, patch: `[{"op": "add", "path": "/foo/0", "value": 1}, {"op": "add", "path": "/foo/0", "value": 0}]`, expected: `{"foo": [0,1]}`},
+		{data: `{"foo": 1}`, patch: `[]`, expected: `{"foo": 1}`},
+		{data: `{"id": 9007199254740993}`, patch: `[{"op": "test", "path": "/id", "value": 9007199254740993}]`, expected: `{"id": 9007199254740993}`},
+		{data: `{"foo": 1}`, patch: `[{"op": "move", "from": "/foo", "path": "/foo"}]`, expected: `{"foo": 1}`},
+	}
+	for _, test := range tests {
+		ret, err := jajson.ApplyPatch([]byte(test.data), []byte(test.patch))
+		t.Require().NoError(err, test.patch)
+		t.Equal(test.expected, string(ret), test.patch)
+	}
+}
+
+func (t *PatchSuite) TestApplyPatchErrors() {
+	data := []byte(`{"foo": "bar", "list": [1], "id": 9007199254740992}`)
+	tests := []struct {
+		patch string
+		index int
+		err   error
+	}{
+		{patch: `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`, index: 0, err: jajson.ErrorWrongPath},
+		{patch: `[{"op": "test", "path": "/foo", "value": "bar"}, {"op": "test", "path": "/foo", "value": 1}]`, index: 1, err: jajson.ErrorPatchTest},
+		{patch: `[{"op": "remove", "path": "/list/1"}]`, index: 0, err: jajson.ErrorWrongPath},
+		{patch: `[{"op": "add", "path": "/list/2", "value": 1}]`, index: 0, err: jajson.ErrorWrongPath},
+		{patch: `[{"op": "add", "path": "/list/01", "value": 1}]`, index: 0, err: jajson.ErrorWrongPath},
+		{patch: `[{"op": "replace", "path": "/baz", "value": 1}]`, index: 0, err: jajson.ErrorWrongPath},
+		{patch: `[{"op": "move", "from": "/list", "path": "/list/0"}]`, index: 0, err: jajson.ErrorPatchOperation},
+		{patch: `[{"op": "move", "from": "/zz", "path": "/zz"}]`, index: 0, err: jajson.ErrorWrongPath},
+		{patch: `[{"op": "move", "from": "/zz", "path": "/zz/a"}]`, index: 0, err: jajson.ErrorWrongPath},
+		{patch: `[{"op": "remove", "path": ""}]`, index: 0, err: jajson.ErrorWrongPath},
+		{patch: `[{"op": "remove", "path": "foo"}]`, index: 0, err: jajson.ErrorWrongPointer},
+		{patch: `[{"op": "remove", "path": "/foo"}, {"op": "invalid", "path": "/foo"}]`, index: 1, err: jajson.ErrorPatchOperation},
+		{patch: `[{"op": "add", "path": "/foo"}]`, index: 0, err: jajson.ErrorWrongPath},
+		{patch: `[{"path": "/foo"}]`, index: 0, err: jajson.ErrorWrongPath},
+		{patch: `[1]`, index: 0, err: jajson.ErrorPatchOperation},
+		{patch: `[{"op": "test", "path": "/id", "value": 9007199254740993}]`, index: 0, err: jajson.ErrorPatchTest},
+	}
+	for _, test := range tests {
+		ret, err := jajson.ApplyPatch(data, []byte(test.patch))
+		t.Nil(ret, test.patch)
+		t.ErrorIs(err, test.err, test.patch)
+		var patchErr jajson.PatchError
+		t.Require().ErrorAs(err, &patchErr, test.patch)
+		t.Equal(test.index, patchErr.Index, test.patch)
+	}
+
+	_, err := jajson.ApplyPatch(data, []byte(`{"op": "remove", "path": "/foo"}`))
+	t.ErrorIs(err, jajson.ErrorWrongValueType)
+	t.False(errors.As(err, new(jajson.PatchError)))
+
+	_, err = jajson.ApplyPatch(data, []byte(`[{"op": "remove", "path": "/foo"},]`))
+	t.ErrorIs(err, jajson.ErrorUnexpectedLexeme)
+
+	_, err = jajson.ApplyPatch(data, []byte("[\n  {\"op\": \"remove\", \"path\": \"/foo\"},\n  {\"op\": \"nop\", \"path\": \"/foo\"}\n]"))
+	var e jajson.Error
+	t.Require().ErrorAs(err, &e)
+	t.Equal(3, e.Line())
+	t.Equal("op", e.Path())
+	t.Equal("Operation: 1. Op: nop. Line: 3. Column: 3. Pos: 40. Path: op. Error: invalid JSON patch operation", err.Error())
+}
+
+func (t *PatchSuite) TestCreatePatch() {
+	tests := []struct {
+		original, modified, expected string
+	}{
+		{original: `{"a": 1}`, modified: `{"a": 1.0}`, expected: `[]`},
+		{original: `{"a": 1, "b": 2}`, modified: `{"b": 3, "c": [1]}`, expected: `[{"op":"remove","path":"/a"},{"op":"replace","path":"/b","value":3},{"op":"add","path":"/c","value":[1]}]`},
+		{original: `{"a/b": {"c~": [1, 2, 3]}}`, modified: `{"a/b": {"c~": [1, 5]}}`, expected: `[{"op":"replace","path":"/a~1b/c~0/1","value":5},{"op":"remove","path":"/a~1b/c~0/2"}]`},
+		{original: `[1]`, modified: `[1, {"x": null}, 3]`, expected: `[{"op":"add","path":"/1","value":{"x": null}},{"op":"add","path":"/2","value":3}]`},
+		{original: `[1, 2, 3]`, modified: `[1]`, expected: `[{"op":"remove","path":"/2"},{"op":"remove","path":"/1"}]`},
+		{original: `{"a": 1}`, modified: `[1]`, expected: `[{"op":"replace","path":"","value":[1]}]`},
+		{original: `{"id": 9007199254740993}`, modified: `{"id": 9007199254740992}`, expected: `[{"op":"replace","path":"/id","value":9007199254740992}]`},
+	}
+	for _, test := range tests {
+		patch, err := jajson.CreatePatch([]byte(test.original), []byte(test.modified))
+		t.Require().NoError(err, test.modified)
+		t.Equal(test.expected, string(patch), test.modified)
+
+		ret, err := jajson.ApplyPatch([]byte(test.original), patch)
+		t.Require().NoError(err, test.modified)
+		back, err := jajson.CreatePatch(ret, []byte(test.modified))
+		t.Require().NoError(err, test.modified)
+		t.Equal(`[]`, string(back), test.modified)
+	}
+
+	_, err := jajson.CreatePatch([]byte(`{`), []byte(`{}`))
+	t.ErrorIs(err, jajson.ErrorUnexpected)
+}
@@ -0,0 +1,146 @@
+package jajson
+
+import "strings"
+
+// GetRawValueByPointer returns part of the original slice with value addressed by RFC 6901 JSON pointer
+func GetRawValueByPointer(data []byte, pointer string) (LexemeType, []byte, error) {
+	return defaultParser.GetRawValueByPointer(data, pointer)
+}
+
+func IsNullByPointer(data []byte, pointer string) (bool, error) {
+	return defaultParser.IsNullByPointer(data, pointer)
+}
+
+func GetStringByPointer(data []byte, pointer string) (string, error) {
+	return defaultParser.GetStringByPointer(data, pointer)
+}
+
+func GetBoolByPointer(data []byte, pointer string) (bool, error) {
+	return defaultParser.GetBoolByPointer(data, pointer)
+}
+
+func GetIntByPointer[T int | int8 | int16 | int32 | int64](data []byte, pointer string) (T, error) {
+	return getValueByPointer(defaultParser, data, pointer, toInt[T])
+}
+
+func GetUIntByPointer[T uint | uint8 | uint16 | uint32 | uint64](data []byte, pointer string) (T, error) {
+	return getValueByPointer(defaultParser, data, pointer, toUInt[T])
+}
+
+func GetFloatByPointer[T float32 | float64](data []byte, pointer string) (T, error) {
+	return getValueByPointer(defaultParser, data, pointer, toFloat[T])
+}
+
+// GetRawValueByPointer returns part of the original slice with value addressed by RFC 6901 JSON pointer.
+// Reference tokens are array indices or object keys depending on the container encountered
+func (p *Parser) GetRawValueByPointer(data []byte, pointer string) (LexemeType, []byte, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return Err, nil, err
+	}
+	if len(data) == 0 {
+		return 0, nil, ErrorEmptyJSON
+	}
+	lex := p.NewTokenizer(data)
+	for i := 0; i < len(tokens); i++ {
+		if err := skipPathPart(lex, tokens[i], pointerIndex); err != nil {
+			return Err, nil, withPath(err, pointer)
+		}
+	}
+	typ, val, err := parseValue(lex)
+	if err != nil {
+		return Err, nil, withPath(err, pointer)
+	}
+	return typ, val, nil
+}
+
+func (p *Parser) IsNullByPointer(data []byte, pointer string) (bool, error) {
+	return getValueByPointer(p, data, pointer, (*Parser).toNull)
+}
+
+func (p *Parser) GetStringByPointer(data []byte, pointer string) (string, error) {
+	return getValueByPointer(p, data, pointer, (*Parser).toString)
+}
+
+func (p *Parser) GetBoolByPointer(data []byte, pointer string) (bool, error) {
+	return getValueByPointer(p, data, pointer, (*Parser).toBool)
+}
+
+func (p *Parser) GetIntByPointer(data []byte, pointer string) (int64, error) {
+	return getValueByPointer(p, data, pointer, toInt[int64])
+}
+
+func (p *Parser) GetUIntByPointer(data []byte, pointer string) (uint64, error) {
+	return getValueByPointer(p, data, pointer, toUInt[uint64])
+}
+
+func (p *Parser) GetFloatByPointer(data []byte, pointer string) (float64, error) {
+	return getValueByPointer(p, data, pointer, toFloat[float64])
+}
+
+// getValueByPointer converts the value found by pointer, errors of conversion are bound to the position of value and the pointer
+func getValueByPointer[T any](p *Parser, data []byte, pointer string, conv func(*Parser, LexemeType, []byte) (T, error)) (T, error) {
+	typ, val, err := p.GetRawValueByPointer(data, pointer)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	ret, err := conv(p, typ, val)
+	if err != nil {
+		return ret, bindError(err, data, val, pointer)
+	}
+	return ret, nil
+}
+
+// parsePointer splits JSON pointer into unescaped reference tokens, empty pointer addresses the whole document
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, withPath(ErrorWrongPointer, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	offset := 1
+	for i, token := range tokens {
+		start := offset
+		offset += len(token) + 1
+		if strings.IndexByte(token, '~') < 0 {
+			continue
+		}
+		var sb strings.Builder
+		for j := 0; j < len(token); j++ {
+			if token[j] != '~' {
+				sb.WriteByte(token[j])
+				continue
+			}
+			if j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1') {
+				return nil, withPath(ErrorWrongPointer.New(start+j), pointer)
+			}
+			if token[j+1] == '0' {
+				sb.WriteByte('~')
+			} else {
+				sb.WriteByte('/')
+			}
+			j++
+		}
+		tokens[i] = sb.String()
+	}
+	return tokens, nil
+}
+
+// pointerIndex parses reference token as array index: 0 or digits without leading zero.
+// Token "-" references the element after the last one, so it is never found
+func pointerIndex(token string) (int, bool) {
+	if len(token) == 0 || len(token) > 18 || (token[0] == '0' && len(token) > 1) {
+		return 0, false
+	}
+	index := 0
+	for i := 0; i < len(token); i++ {
+		if !isDigit(token[i]) {
+			return 0, false
+		}
+		index = index*10 + int(token[i]-'0')
+	}
+	return index, true
+}
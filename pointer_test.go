@@ -0,0 +1,70 @@
+package jajson_test
+
+import (
+	"testing"
+
+	"github.com/aleksandrzhukovskii/jajson"
+	"github.com/stretchr/testify/suite"
+)
+
+type PointerSuite struct {
+	suite.Suite
+}
+
+func TestPointer(t *testing.T) {
+	suite.Run(t, new(PointerSuite))
+}
+
+func (t *PointerSuite) TestGetByPointer() {
+	str := []byte(`{"items": [{"name": "first", "qty": 2}, {"name": "second", "ok": true}], "a/b": 1, "m~n": 2.5, "0": null, "": "empty"}`)
+
+	name, err := jajson.GetStringByPointer(str, "/items/1/name")
+	t.NoError(err)
+	t.Equal("second", name)
+
+	qty, err := jajson.GetIntByPointer[int](str, "/items/0/qty")
+	t.NoError(err)
+	t.Equal(2, qty)
+
+	uqty, err := jajson.GetUIntByPointer[uint8](str, "/items/0/qty")
+	t.NoError(err)
+	t.Equal(uint8(2), uqty)
+
+	ok, err := jajson.GetBoolByPointer(str, "/items/1/ok")
+	t.NoError(err)
+	t.True(ok)
+
+	one, err := jajson.GetIntByPointer[int](str, "/a~1b")
+	t.NoError(err)
+	t.Equal(1, one)
+
+	f, err := jajson.GetFloatByPointer[float64](str, "/m~0n")
+	t.NoError(err)
+	t.Equal(2.5, f)
+
+	isNull, err := jajson.IsNullByPointer(str, "/0")
+	t.NoError(err)
+	t.True(isNull)
+
+	empty, err := jajson.GetStringByPointer(str, "/")
+	t.NoError(err)
+	t.Equal("empty", empty)
+
+	typ, raw, err := jajson.GetRawValueByPointer(str, "")
+	t.NoError(err)
+	t.Equal(jajson.Object, typ)
+	t.Equal(str, raw)
+}
+
+func (t *PointerSuite) TestGetByPointerErrors() {
+	str := []byte(`{"items": [1, 2]}`)
+	for _, pointer := range []string{"/items/2", "/items/-", "/items/01", "/items/-1", "/missing"} {
+		_, _, err := jajson.GetRawValueByPointer(str, pointer)
+		t.ErrorContains(err, "path not found", pointer)
+		t.ErrorContains(err, pointer, pointer)
+	}
+	for _, pointer := range []string{"items", "/items~2", "/items~"} {
+		_, _, err := jajson.GetRawValueByPointer(str, pointer)
+		t.ErrorContains(err, "invalid JSON pointer", pointer)
+	}
+}
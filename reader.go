@@ -0,0 +1,177 @@
+package jajson
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"unicode/utf8"
+)
+
+// readerWindow is the initial size of the window of streaming tokenizer, it grows when a value does not fit
+const readerWindow = 4096
+
+// readerLookahead is the amount of bytes read ahead before lexing a token, longer tokens are lexed again
+const readerLookahead = 64
+
+// readerChunk is the maximum amount of bytes requested from the reader at once unless more is needed
+// for the current token, so the reader is consumed only a little beyond the found value
+const readerChunk = 256
+
+// errorLookback is the longest distance between the window end and error caused by it,
+// it is the length of \U0001F600 escape with the rest of the rune
+const errorLookback = 16
+
+// GetRawValueFromReader returns copy of the value found by path reading r only until the value is complete
+func GetRawValueFromReader(r io.Reader, path ...string) (LexemeType, []byte, error) {
+	return defaultParser.GetRawValueFromReader(r, path...)
+}
+
+// GetRawValueFromReader returns copy of the value found by path reading r only until the value is complete
+// with a small lookahead. Only the skipped part of the current value and the found value are kept in memory
+func (p *Parser) GetRawValueFromReader(r io.Reader, path ...string) (LexemeType, []byte, error) {
+	lex := p.newReaderLexer(r)
+	if err := lex.fill(1); err != nil {
+		return Err, nil, err
+	} else if lex.eof && len(lex.src) == 0 {
+		return 0, nil, ErrorEmptyJSON
+	}
+	if len(path) > 0 {
+		if err := skipPath(lex, path); err != nil {
+			return Err, nil, withPath(err, formatPath(path))
+		}
+	}
+	lxm, err := lex.peek()
+	if err == nil {
+		lex.keep = lxm.BytePos
+		var val []byte
+		var typ LexemeType
+		if typ, val, err = parseValue(lex); err == nil {
+			return typ, append([]byte(nil), val...), nil
+		}
+	}
+	if len(path) > 0 {
+		return Err, nil, withPath(err, formatPath(path))
+	}
+	return Err, nil, err
+}
+
+func (p *Parser) newReaderLexer(r io.Reader) *Tokenizer {
+	lex := p.NewTokenizer(make([]byte, 0, readerWindow))
+	lex.reader = r
+	return lex
+}
+
+// nextTokenFromReader lexes the next token, the window is refilled and the token is lexed again
+// when the token or the error may change with more data
+func (t *Tokenizer) nextTokenFromReader() (Token, error) {
+	for min := readerLookahead; ; {
+		if err := t.fill(min); err != nil {
+			return Token{}, err
+		}
+		pos, bytePos := t.pos, t.bytePos
+		lxm, err := t.scanToken()
+		if t.eof || !t.mayContinue(lxm, err) {
+			return lxm, err
+		}
+		t.pos, t.bytePos = pos, bytePos
+		min = 2*t.unread() + readerLookahead
+	}
+}
+
+// unread returns the amount of bytes in the window after the current position
+func (t *Tokenizer) unread() int {
+	return len(t.src) - (t.bytePos - t.offset)
+}
+
+// mayContinue reports whether the scanned token or error is possibly cut by the window end
+func (t *Tokenizer) mayContinue(lxm Token, err error) bool {
+	if err != nil {
+		var e Error
+		return t.isComment() || (errors.As(err, &e) && e.bytePos+errorLookback >= t.offset+len(t.src))
+	}
+	switch lxm.Type {
+	case Int, Float, Identifier:
+		return len(t.data) == 0
+	case Bool, Null:
+		return t.json5 && len(t.data) == 0
+	}
+	return false
+}
+
+// fill reads data into the window until at least min bytes are unread or the reader ends,
+// when the window is full data before the current token and the kept value is discarded
+func (t *Tokenizer) fill(min int) error {
+	for !t.eof && t.unread() < min {
+		if len(t.src) == cap(t.src) {
+			start := t.bytePos
+			if t.keep >= 0 && t.keep < start {
+				start = t.keep
+			}
+			// the beginning of error context is kept as well
+			if n := start - t.offset - contextRadius*utf8.UTFMax; n > 0 {
+				t.discard(n)
+			}
+			if len(t.src) > cap(t.src)/2 {
+				buf := make([]byte, len(t.src), 2*cap(t.src))
+				copy(buf, t.src)
+				t.src = buf
+			}
+		}
+		size := readerChunk
+		if need := min - t.unread(); need > size {
+			size = need
+		}
+		end := len(t.src) + size
+		if end > cap(t.src) {
+			end = cap(t.src)
+		}
+		n, err := t.reader.Read(t.src[len(t.src):end])
+		t.src = t.src[:len(t.src)+n]
+		if err == io.EOF {
+			t.eof = true
+		} else if err != nil {
+			t.data = t.src[t.bytePos-t.offset:]
+			return err
+		}
+	}
+	t.data = t.src[t.bytePos-t.offset:]
+	return nil
+}
+
+// discard removes n bytes from the beginning of the window remembering lines and column for errors
+func (t *Tokenizer) discard(n int) {
+	discarded := t.src[:n]
+	if i := bytes.LastIndexByte(discarded, '\n'); i >= 0 {
+		t.lines += bytes.Count(discarded, []byte{'\n'})
+		t.column = utf8.RuneCount(discarded[i+1:])
+	} else {
+		t.column += utf8.RuneCount(discarded)
+	}
+	t.offset += n
+	t.src = t.src[:copy(t.src, t.src[n:])]
+	t.data = t.src[t.bytePos-t.offset:]
+}
+
+// lexerState is the position of Tokenizer restored by rewind
+type lexerState struct {
+	pos, bytePos, keep int
+}
+
+// save returns the current position, data from byte offset start is kept in the window until rewind
+func (t *Tokenizer) save(start int) lexerState {
+	state := lexerState{pos: t.pos, bytePos: t.bytePos, keep: t.keep}
+	if t.peeked {
+		state.pos, state.bytePos = t.peekToken.Pos, t.peekToken.BytePos
+	}
+	if t.keep < 0 || start < t.keep {
+		t.keep = start
+	}
+	return state
+}
+
+// rewind returns the Tokenizer to the saved position
+func (t *Tokenizer) rewind(state lexerState) {
+	t.pos, t.bytePos, t.keep = state.pos, state.bytePos, state.keep
+	t.data = t.src[t.bytePos-t.offset:]
+	t.peeked, t.peekToken, t.peekError = false, Token{}, nil
+}
@@ -0,0 +1,136 @@
+package jajson_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/aleksandrzhukovskii/jajson"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReaderSuite struct {
+	suite.Suite
+}
+
+func TestReader(t *testing.T) {
+	suite.Run(t, new(ReaderSuite))
+}
+
+// countingReader counts bytes read from the underlying reader
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += n
+	return n, err
+}
+
+func (t *ReaderSuite) TestGetRawValueFromReader() {
+	var sb strings.Builder
+	sb.WriteString("{\n  \"items\": [")
+	for i := 0; i < 500; i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, `{"id": %d, "name": "item ☺ %d", "tags": ["a", "b"], "price": %d.5e-1}`, i, i, i*1000)
+	}
+	sb.WriteString("],\n  \"count\": 500, \"flag\": true, \"none\": null, \"str\": \"\\\"x\\\"\"\n}")
+	data := []byte(sb.String())
+
+	paths := [][]string{nil, {"items"}, {"items", "[0]"}, {"items", "[-1]", "name"}, {"items", "[250]", "tags"},
+		{"items", "[499]", "price"}, {"count"}, {"flag"}, {"none"}, {"str"}, {"items", "[-501]"}, {"missing"},
+		{"count", "x"}}
+	for _, path := range paths {
+		expectedTyp, expected, expectedErr := jajson.GetRawValue(data, path...)
+		for _, r := range []io.Reader{bytes.NewReader(data), iotest.OneByteReader(bytes.NewReader(data)),
+			iotest.DataErrReader(bytes.NewReader(data))} {
+			typ, val, err := jajson.GetRawValueFromReader(r, path...)
+			t.Equal(expectedTyp, typ, path)
+			t.Equal(string(expected), string(val), path)
+			if expectedErr == nil {
+				t.NoError(err)
+				continue
+			}
+			// context after the error is limited to the data read so far
+			var e, expected jajson.Error
+			t.Require().ErrorAs(err, &e)
+			t.Require().ErrorAs(expectedErr, &expected)
+			t.Equal(expected.Error(), e.Error(), path)
+			t.Equal(expected.BytePos(), e.BytePos(), path)
+			t.True(strings.HasPrefix(expected.Context(), strings.SplitN(e.Context(), "\n", 2)[0]), path)
+		}
+	}
+}
+
+func (t *ReaderSuite) TestStopsReading() {
+	data := []byte(`{"header": {"version": 2}, "rows": [` + strings.Repeat(`[1, 2, 3], `, 100000) + `[]], "footer": "` +
+		strings.Repeat("x", 1000) + `"}`)
+	r := &countingReader{r: iotest.OneByteReader(bytes.NewReader(data))}
+	typ, val, err := jajson.GetRawValueFromReader(r, "header")
+	t.NoError(err)
+	t.Equal(jajson.Object, typ)
+	t.Equal(`{"version": 2}`, string(val))
+	t.Less(r.n, 200)
+
+	r = &countingReader{r: iotest.OneByteReader(bytes.NewReader(data))}
+	typ, val, err = jajson.GetRawValueFromReader(r, "rows", "[-1]")
+	t.NoError(err)
+	t.Equal(jajson.Array, typ)
+	t.Equal(`[]`, string(val))
+	t.Less(r.n, len(data)-900)
+}
+
+func (t *ReaderSuite) TestLookahead() {
+	big := `{"a": [` + strings.Repeat(`"0123456789", `, 8000) + `""]}`
+	tail := strings.Repeat(`, 1`, 100000) + `]`
+	for _, value := range []string{`{"a": 1}`, big} {
+		data := `[` + value + tail
+		r := &countingReader{r: strings.NewReader(data)}
+		_, val, err := jajson.GetRawValueFromReader(r, "[0]")
+		t.Require().NoError(err)
+		t.Equal(value, string(val))
+		t.LessOrEqual(r.n, 1+len(value)+512)
+	}
+}
+
+func (t *ReaderSuite) TestErrors() {
+	_, _, err := jajson.GetRawValueFromReader(strings.NewReader(""))
+	t.Equal(jajson.ErrorEmptyJSON, err)
+
+	data := strings.Repeat(" ", 5000) + "\n" + strings.Repeat("\n", 3000) + `{"a": [1, 2,` + "\n\t☺ tru]}"
+	_, _, expectedErr := jajson.GetRawValue([]byte(data), "a", "[3]")
+	_, _, err = jajson.GetRawValueFromReader(iotest.HalfReader(strings.NewReader(data)), "a", "[3]")
+	var e, expected jajson.Error
+	t.Require().ErrorAs(err, &e)
+	t.Require().ErrorAs(expectedErr, &expected)
+	t.Equal(expected.Line(), e.Line())
+	t.Equal(expected.Column(), e.Column())
+	t.Equal(expected.Pos(), e.Pos())
+	t.Equal(expected.BytePos(), e.BytePos())
+	t.Equal(expected.Error(), e.Error())
+
+	readErr := errors.New("read failed")
+	_, _, err = jajson.GetRawValueFromReader(io.MultiReader(strings.NewReader(`{"a": `), iotest.ErrReader(readErr)), "a")
+	t.ErrorIs(err, readErr)
+}
+
+func (t *ReaderSuite) TestOptions() {
+	data := "{/* " + strings.Repeat("comment ", 1000) + "*/ key: 'value', num: 0x" + strings.Repeat("0", 5000) + "1F,}"
+	parser := jajson.Parser{JSON5: true}
+	typ, val, err := parser.GetRawValueFromReader(iotest.OneByteReader(strings.NewReader(data)), "num")
+	t.NoError(err)
+	t.Equal(jajson.Int, typ)
+	t.Equal("0x"+strings.Repeat("0", 5000)+"1F", string(val))
+
+	_, val, err = parser.GetRawValueFromReader(strings.NewReader(data), "key")
+	t.NoError(err)
+	t.Equal(`'value'`, string(val))
+}
@@ -0,0 +1,105 @@
+package jajson_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/aleksandrzhukovskii/jajson"
+	"github.com/stretchr/testify/suite"
+)
+
+type TokenizerSuite struct {
+	suite.Suite
+}
+
+func TestTokenizer(t *testing.T) {
+	suite.Run(t, new(TokenizerSuite))
+}
+
+func (t *TokenizerSuite) TestNext() {
+	data := []byte(`{"ключ": [1, 2.5, true, null]} `)
+	tok := jajson.NewTokenizer(data)
+	expected := []jajson.Token{
+		{Type: jajson.OpenCurve, Value: []byte("{"), Pos: 0, BytePos: 0},
+		{Type: jajson.String, Value: []byte(`"ключ"`), Pos: 1, BytePos: 1},
+		{Type: jajson.Colon, Value: []byte(":"), Pos: 7, BytePos: 11},
+		{Type: jajson.OpenBracket, Value: []byte("["), Pos: 9, BytePos: 13},
+		{Type: jajson.Int, Value: []byte("1"), Pos: 10, BytePos: 14},
+		{Type: jajson.Comma, Value: []byte(","), Pos: 11, BytePos: 15},
+		{Type: jajson.Float, Value: []byte("2.5"), Pos: 13, BytePos: 17},
+		{Type: jajson.Comma, Value: []byte(","), Pos: 16, BytePos: 20},
+		{Type: jajson.Bool, Value: []byte("true"), Pos: 18, BytePos: 22},
+		{Type: jajson.Comma, Value: []byte(","), Pos: 22, BytePos: 26},
+		{Type: jajson.Null, Value: []byte("null"), Pos: 24, BytePos: 28},
+		{Type: jajson.CloseBracket, Value: []byte("]"), Pos: 28, BytePos: 32},
+		{Type: jajson.CloseCurve, Value: []byte("}"), Pos: 29, BytePos: 33},
+	}
+	for _, exp := range expected {
+		peeked, err := tok.Peek()
+		t.Require().NoError(err)
+		next, err := tok.Next()
+		t.Require().NoError(err)
+		t.Equal(peeked, next)
+		t.Equal(exp.Type, next.Type)
+		t.Equal(string(exp.Value), string(next.Value))
+		// values are parts of the tokenized data
+		t.Equal(exp.BytePos, jajson.Offset(data, next.Value))
+		t.Equal(exp.Pos, next.Pos)
+		t.Equal(exp.BytePos, next.BytePos)
+	}
+	_, err := tok.Peek()
+	t.ErrorIs(err, io.EOF)
+	_, err = tok.Next()
+	t.ErrorIs(err, io.EOF)
+
+	_, err = jajson.NewTokenizer([]byte(" \n")).Next()
+	t.ErrorIs(err, io.EOF)
+}
+
+func (t *TokenizerSuite) TestErrors() {
+	tok := jajson.NewTokenizer([]byte(`[tru]`))
+	_, err := tok.Next()
+	t.NoError(err)
+	_, err = tok.Next()
+	t.ErrorIs(err, jajson.ErrorUnexpected)
+	t.NotErrorIs(err, io.EOF)
+	var e jajson.Error
+	t.Require().ErrorAs(err, &e)
+	t.Equal(4, e.Pos())
+
+	_, err = jajson.NewTokenizer([]byte(`// c`)).Next()
+	t.ErrorIs(err, jajson.ErrorUnexpected)
+}
+
+func (t *TokenizerSuite) TestOptions() {
+	p := jajson.Parser{JSON5: true}
+	tok := p.NewTokenizer([]byte("// c\n{key: 'v'}"))
+	types := []jajson.LexemeType{jajson.OpenCurve, jajson.Identifier, jajson.Colon, jajson.String, jajson.CloseCurve}
+	for _, typ := range types {
+		next, err := tok.Next()
+		t.Require().NoError(err)
+		t.Equal(typ, next.Type)
+	}
+	_, err := tok.Next()
+	t.ErrorIs(err, io.EOF)
+}
+
+func (t *TokenizerSuite) TestString() {
+	t.Equal("{", jajson.OpenCurve.String())
+	t.Equal("}", jajson.CloseCurve.String())
+	t.Equal("[", jajson.OpenBracket.String())
+	t.Equal("]", jajson.CloseBracket.String())
+	t.Equal(":", jajson.Colon.String())
+	t.Equal(",", jajson.Comma.String())
+	t.Equal("String", jajson.String.String())
+	t.Equal("Identifier", jajson.Identifier.String())
+	t.Equal("LexemeType(200)", jajson.LexemeType(200).String())
+}
+
+func (t *TokenizerSuite) TestLexemeTypeValues() {
+	// values of types existing before Null are stable
+	t.Equal(jajson.LexemeType(11), jajson.Object)
+	t.Equal(jajson.LexemeType(12), jajson.Array)
+	t.Equal(jajson.LexemeType(13), jajson.Err)
+	t.Equal("Null", jajson.Null.String())
+}
@@ -0,0 +1,171 @@
+package jajson
+
+import (
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// unescape decodes string token with quotes. When there are no escapes it returns subslice of val without copying,
+// otherwise decoded string is appended to buf. Lone UTF-16 surrogates are replaced with U+FFFD
+// unless strictSurrogates is set. goEscapes selects Go string literal rules instead of JSON ones,
+// json5 allows single quotes, JSON5 escapes and identifiers which are returned as is
+func unescape(val, buf []byte, goEscapes, json5, strictSurrogates bool) ([]byte, error) {
+	if goEscapes {
+		str, err := strconv.Unquote(string(val))
+		if err != nil {
+			return nil, ErrorWrongQuote.wrap(err)
+		}
+		return append(buf, str...), nil
+	}
+	if json5 && len(val) > 0 && val[0] != '"' && val[0] != '\'' {
+		return val, nil
+	}
+	if len(val) < 2 || !(val[0] == '"' || (json5 && val[0] == '\'')) || val[len(val)-1] != val[0] {
+		return nil, ErrorWrongQuote
+	}
+	val = val[1 : len(val)-1]
+	i := 0
+	for i < len(val) && val[i] != '\\' {
+		i++
+	}
+	if i == len(val) {
+		return val, nil
+	}
+	buf = append(buf, val[:i]...)
+	for i < len(val) {
+		if val[i] != '\\' {
+			buf = append(buf, val[i])
+			i++
+			continue
+		}
+		if i+1 == len(val) {
+			return nil, ErrorUnexpected
+		}
+		switch val[i+1] {
+		case '"', '\\', '/':
+			buf = append(buf, val[i+1])
+		case 'b':
+			buf = append(buf, '\b')
+		case 'f':
+			buf = append(buf, '\f')
+		case 'n':
+			buf = append(buf, '\n')
+		case 'r':
+			buf = append(buf, '\r')
+		case 't':
+			buf = append(buf, '\t')
+		case 'u':
+			r, ok := unhex4(val[i:])
+			if !ok {
+				return nil, ErrorUnexpected
+			}
+			i += 6
+			if utf16.IsSurrogate(r) {
+				if r2, ok := unhex4(val[i:]); ok && r < 0xDC00 {
+					if dec := utf16.DecodeRune(r, r2); dec != utf8.RuneError {
+						r = dec
+						i += 6
+					}
+				}
+				if utf16.IsSurrogate(r) {
+					if strictSurrogates {
+						return nil, ErrorLoneSurrogate
+					}
+					r = utf8.RuneError
+				}
+			}
+			buf = utf8.AppendRune(buf, r)
+			continue
+		default:
+			if !json5 {
+				return nil, ErrorUnexpected
+			}
+			var err error
+			if buf, i, err = unescapeJSON5(val, buf, i); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		i += 2
+	}
+	return buf, nil
+}
+
+// unescapeJSON5 decodes JSON5 escape at val[i:] which is not JSON one and returns the index after it
+func unescapeJSON5(val, buf []byte, i int) ([]byte, int, error) {
+	switch c := val[i+1]; c {
+	case 'v':
+		return append(buf, '\v'), i + 2, nil
+	case '0':
+		return append(buf, 0), i + 2, nil
+	case 'x':
+		if i+4 > len(val) {
+			return nil, 0, ErrorUnexpected
+		}
+		hi, ok1 := unhex(val[i+2])
+		lo, ok2 := unhex(val[i+3])
+		if !ok1 || !ok2 {
+			return nil, 0, ErrorUnexpected
+		}
+		return utf8.AppendRune(buf, hi<<4|lo), i + 4, nil
+	case '\n':
+		return buf, i + 2, nil
+	case '\r':
+		if i+2 < len(val) && val[i+2] == '\n' {
+			return buf, i + 3, nil
+		}
+		return buf, i + 2, nil
+	default:
+		r, size := utf8.DecodeRune(val[i+1:])
+		if r == '\u2028' || r == '\u2029' {
+			return buf, i + 1 + size, nil
+		}
+		return append(buf, val[i+1:i+1+size]...), i + 1 + size, nil
+	}
+}
+
+// unhex4 decodes \uXXXX escape at the beginning of val
+func unhex4(val []byte) (rune, bool) {
+	if len(val) < 6 || val[0] != '\\' || val[1] != 'u' {
+		return 0, false
+	}
+	var r rune
+	for j := 2; j < 6; j++ {
+		x, ok := unhex(val[j])
+		if !ok {
+			return 0, false
+		}
+		r = r<<4 | x
+	}
+	return r, true
+}
+
+// appendQuote appends s to buf as JSON string literal with quotes
+func appendQuote(buf []byte, s []byte) []byte {
+	const hex = "0123456789abcdef"
+	buf = append(buf, '"')
+	for _, c := range s {
+		switch c {
+		case '"', '\\':
+			buf = append(buf, '\\', c)
+		case '\b':
+			buf = append(buf, '\\', 'b')
+		case '\f':
+			buf = append(buf, '\\', 'f')
+		case '\n':
+			buf = append(buf, '\\', 'n')
+		case '\r':
+			buf = append(buf, '\\', 'r')
+		case '\t':
+			buf = append(buf, '\\', 't')
+		default:
+			if c < 0x20 {
+				buf = append(buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			} else {
+				buf = append(buf, c)
+			}
+		}
+	}
+	return append(buf, '"')
+}
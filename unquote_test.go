@@ -0,0 +1,92 @@
+package jajson
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type UnquoteSuite struct {
+	suite.Suite
+}
+
+func TestUnquote(t *testing.T) {
+	suite.Run(t, new(UnquoteSuite))
+}
+
+func (t *UnquoteSuite) TestUnescape() {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{value: `""`, expected: ``},
+		{value: `"abc"`, expected: `abc`},
+		{value: `"a\/b"`, expected: `a/b`},
+		{value: `"\"\\\b\f\n\r\t"`, expected: "\"\\\b\f\n\r\t"},
+		{value: `"Aé☺"`, expected: "Aé☺"},
+		{value: `"\uD83D\uDE00"`, expected: "\U0001F600"},
+		{value: `"x\uD83D\uDE00y"`, expected: "x\U0001F600y"},
+		{value: `"\uD83D"`, expected: "\uFFFD"},
+		{value: `"\uDE00\uD83D"`, expected: "\uFFFD\uFFFD"},
+		{value: `"\uD83Dx"`, expected: "\uFFFDx"},
+		{value: `"\uD83D\u0041"`, expected: "\uFFFDA"},
+	}
+	for _, test := range tests {
+		val, err := unescape([]byte(test.value), nil, false, false, false)
+		t.NoError(err, test.value)
+		t.Equal(test.expected, string(val), test.value)
+	}
+
+	_, err := unescape([]byte(`"\uD83D"`), nil, false, false, true)
+	t.Equal(ErrorLoneSurrogate, err)
+	val, err := unescape([]byte(`"\uD83D\uDE00"`), nil, false, false, true)
+	t.NoError(err)
+	t.Equal("\U0001F600", string(val))
+}
+
+func (t *UnquoteSuite) TestUnescapeJSON5() {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{value: `'abc'`, expected: `abc`},
+		{value: `'"\''`, expected: `"'`},
+		{value: `"\v\0\x41\q\☺"`, expected: "\v\x00Aq☺"},
+		{value: "'a\\\nb\\\r\nc\\\u2028d'", expected: "abcd"},
+		{value: `Identifier`, expected: `Identifier`},
+	}
+	for _, test := range tests {
+		val, err := unescape([]byte(test.value), nil, false, true, false)
+		t.NoError(err, test.value)
+		t.Equal(test.expected, string(val), test.value)
+	}
+
+	_, err := unescape([]byte(`'abc'`), nil, false, false, false)
+	t.Equal(ErrorWrongQuote, err)
+}
+
+func (t *UnquoteSuite) TestUnescapeNoCopy() {
+	data := []byte(`"hello world"`)
+	val, err := unescape(data, nil, false, false, false)
+	t.NoError(err)
+	t.Same(&data[1], &val[0])
+
+	var buf [64]byte
+	escaped := []byte(`"hello\nworld"`)
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = unescape(data, buf[:0], false, false, false)
+		_, _ = unescape(escaped, buf[:0], false, false, false)
+	})
+	t.Zero(allocs)
+}
+
+func (t *UnquoteSuite) TestUnescapeGoEscapes() {
+	val, err := unescape([]byte(`"\x41\u263a"`), nil, true, false, false)
+	t.Require().NoError(err)
+	t.Equal("A☺", string(val))
+
+	_, err = unescape([]byte(`"a\qb"`), nil, true, false, false)
+	t.ErrorIs(err, ErrorWrongQuote)
+	t.ErrorIs(err, strconv.ErrSyntax)
+}
@@ -0,0 +1,28 @@
+package jajson
+
+// Validate checks that data is exactly one JSON value surrounded only by whitespace
+func Validate(data []byte) error {
+	return defaultParser.Validate(data)
+}
+
+// Valid reports whether data is exactly one JSON value surrounded only by whitespace
+func Valid(data []byte) bool {
+	return defaultParser.Validate(data) == nil
+}
+
+// Validate checks that data is exactly one JSON value surrounded only by whitespace
+func (p *Parser) Validate(data []byte) error {
+	if len(data) == 0 {
+		return ErrorEmptyJSON
+	}
+	lex := p.NewTokenizer(data)
+	if _, _, err := parseValue(lex); err != nil {
+		return err
+	}
+	return lex.skipTrailing()
+}
+
+// Valid reports whether data is exactly one JSON value surrounded only by whitespace
+func (p *Parser) Valid(data []byte) bool {
+	return p.Validate(data) == nil
+}
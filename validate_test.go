@@ -0,0 +1,67 @@
+package jajson_test
+
+import (
+	"testing"
+
+	"github.com/aleksandrzhukovskii/jajson"
+	"github.com/stretchr/testify/suite"
+)
+
+type ValidateSuite struct {
+	suite.Suite
+}
+
+func TestValidate(t *testing.T) {
+	suite.Run(t, new(ValidateSuite))
+}
+
+func (t *ValidateSuite) TestValid() {
+	for _, data := range []string{`{"a":1}`, "  [1, 2.5e3, null, true, \"x\"]\n\t ", `0`, `"str"`, `{}`, `[]`, ` null `} {
+		t.NoError(jajson.Validate([]byte(data)), data)
+		t.True(jajson.Valid([]byte(data)), data)
+	}
+}
+
+func (t *ValidateSuite) TestInvalid() {
+	t.Equal(jajson.ErrorEmptyJSON, jajson.Validate(nil))
+
+	tests := []struct {
+		data    string
+		message string
+		pos     int
+		line    int
+		col     int
+	}{
+		{data: `   `, message: "unexpected symbol or end of JSON", pos: 2, line: 1, col: 3},
+		{data: `{"a":1} xyz`, message: "unexpected data after JSON value", pos: 8, line: 1, col: 9},
+		{data: `123abc`, message: "unexpected data after JSON value", pos: 3, line: 1, col: 4},
+		{data: `[1] [2]`, message: "unexpected data after JSON value", pos: 4, line: 1, col: 5},
+		{data: `{"a":1}}`, message: "unexpected data after JSON value", pos: 7, line: 1, col: 8},
+		{data: `{"a":1,}`, message: "unexpected lexeme while parsing JSON", pos: 7, line: 1, col: 8},
+		{data: "[1,\n  2,\n  ☺]", message: "unexpected symbol or end of JSON", pos: 11, line: 3, col: 3},
+		{data: "{\"a\": \"x\u0001\"}", message: "unexpected symbol or end of JSON", pos: 8, line: 1, col: 9},
+		{data: `[1, 2.e5]`, message: "unexpected symbol or end of JSON", pos: 6, line: 1, col: 7},
+		{data: "\u00a0{}", message: "unexpected symbol or end of JSON", pos: 0, line: 1, col: 1},
+		{data: "{}\v", message: "unexpected data after JSON value", pos: 2, line: 1, col: 3},
+		{data: "[1,\u00a0 2]", message: "unexpected symbol or end of JSON", pos: 3, line: 1, col: 4},
+		{data: "\f1", message: "unexpected symbol or end of JSON", pos: 0, line: 1, col: 1},
+	}
+	for _, test := range tests {
+		err := jajson.Validate([]byte(test.data))
+		t.ErrorContains(err, test.message, test.data)
+		var e jajson.Error
+		t.Require().ErrorAs(err, &e)
+		t.Equal(test.pos, e.Pos(), test.data)
+		t.Equal(test.line, e.Line(), test.data)
+		t.Equal(test.col, e.Column(), test.data)
+		t.False(jajson.Valid([]byte(test.data)), test.data)
+	}
+}
+
+func (t *ValidateSuite) TestWhitespace() {
+	parser := jajson.Parser{JSON5: true}
+	for _, data := range []string{"\u00a0{}", "{}\v", "[1,\u00a0 2]", "\f1", "\uFEFF[\u2028]"} {
+		t.False(jajson.Valid([]byte(data)), data)
+		t.True(parser.Valid([]byte(data)), data)
+	}
+}
@@ -0,0 +1,288 @@
+package jajson
+
+import "errors"
+
+// PathValue is a value found by one of paths of GetValues, Value is part of the original slice
+type PathValue struct {
+	Type  LexemeType
+	Value []byte
+	// Err is the error of the path, the other paths are found independently
+	Err error
+}
+
+// GetValues returns values of all paths in the order of paths
+func GetValues(data []byte, paths ...[]string) []PathValue {
+	return defaultParser.GetValues(data, paths...)
+}
+
+// GetValues returns values of all paths in the order of paths scanning data once. Only containers which
+// paths go into are walked member by member, the scan stops as soon as all values are found
+func (p *Parser) GetValues(data []byte, paths ...[]string) []PathValue {
+	w := valuesWalker{paths: paths, values: make([]PathValue, len(paths)), done: make([]bool, len(paths))}
+	root := &pathNode{}
+	for i, path := range paths {
+		root.add(path, i)
+	}
+	w.remaining = len(paths)
+	if len(paths) == 0 {
+		return w.values
+	}
+	if len(data) == 0 {
+		w.fail(root, ErrorEmptyJSON)
+		return w.values
+	}
+	w.lex = p.NewTokenizer(data)
+	if err := w.walk(root); err != nil && !errors.Is(err, errAllFound) {
+		w.fail(root, err)
+	}
+	return w.values
+}
+
+// errAllFound stops the scan of GetValues
+var errAllFound = errors.New("all values are found")
+
+// pathNode is a node of the tree of paths, paths are the indices of paths ending at the node
+type pathNode struct {
+	part     string
+	paths    []int
+	children []*pathNode
+	found    bool
+}
+
+func (n *pathNode) add(path []string, index int) {
+	if len(path) == 0 {
+		n.paths = append(n.paths, index)
+		return
+	}
+	for _, child := range n.children {
+		if child.part == path[0] {
+			child.add(path[1:], index)
+			return
+		}
+	}
+	child := &pathNode{part: path[0]}
+	n.children = append(n.children, child)
+	child.add(path[1:], index)
+}
+
+// merge adds paths of other to n
+func (n *pathNode) merge(other *pathNode) {
+	n.paths = append(n.paths, other.paths...)
+	other.paths = nil
+next:
+	for _, oc := range other.children {
+		for _, child := range n.children {
+			if child.part == oc.part {
+				child.merge(oc)
+				continue next
+			}
+		}
+		n.children = append(n.children, oc)
+	}
+	other.children = nil
+}
+
+type valuesWalker struct {
+	lex       *Tokenizer
+	paths     [][]string
+	values    []PathValue
+	done      []bool
+	remaining int
+}
+
+// set stores the value of paths ending at node, errAllFound is returned when it was the last one
+func (w *valuesWalker) set(node *pathNode, typ LexemeType, val []byte) error {
+	for _, i := range node.paths {
+		w.values[i] = PathValue{Type: typ, Value: val}
+		w.done[i] = true
+		w.remaining--
+	}
+	if w.remaining == 0 {
+		return errAllFound
+	}
+	return nil
+}
+
+// fail stores err for all paths going through node which are not found yet
+func (w *valuesWalker) fail(node *pathNode, err error) {
+	for _, i := range node.paths {
+		if !w.done[i] {
+			if len(w.paths[i]) > 0 {
+				w.values[i] = PathValue{Type: Err, Err: withPath(err, formatPath(w.paths[i]))}
+			} else {
+				w.values[i] = PathValue{Type: Err, Err: err}
+			}
+			w.done[i] = true
+			w.remaining--
+		}
+	}
+	for _, child := range node.children {
+		w.fail(child, err)
+	}
+}
+
+// walk parses the value of node, values of paths going into it are stored on the way
+func (w *valuesWalker) walk(node *pathNode) error {
+	if len(node.children) == 0 {
+		typ, val, err := parseValue(w.lex)
+		if err != nil {
+			return err
+		}
+		return w.set(node, typ, val)
+	}
+	lxm, err := w.lex.next()
+	if err != nil {
+		return err
+	}
+	var typ LexemeType
+	var end Token
+	switch lxm.Type {
+	case OpenCurve:
+		typ = Object
+		end, err = w.walkObject(node)
+	case OpenBracket:
+		typ = Array
+		end, err = w.walkArray(node, lxm)
+	case String, Int, Float, Bool, Null:
+		for _, child := range node.children {
+			expected := Object
+			if _, ok := pathIndex(child.part); ok {
+				expected = Array
+			}
+			w.fail(child, w.lex.lexemeError(ErrorWrongPath.withTypes(expected, lxm.Type), lxm))
+		}
+		return w.set(node, lxm.Type, lxm.Value)
+	default:
+		return w.lex.lexemeError(ErrorUnexpectedLexeme, lxm)
+	}
+	if err != nil {
+		return err
+	}
+	return w.set(node, typ, w.lex.slice(lxm.BytePos, end.BytePos+1))
+}
+
+// walkObject walks the rest of object and returns its closing token
+func (w *valuesWalker) walkObject(node *pathNode) (Token, error) {
+	lex := w.lex
+	lxm, err := lex.next()
+	if err != nil {
+		return lxm, err
+	}
+	var buf [64]byte
+	for lxm.Type != CloseCurve {
+		if !lex.isKey(lxm) {
+			return lxm, lex.lexemeError(ErrorUnexpectedLexeme, lxm)
+		}
+		key, err := unescape(lxm.Value, buf[:0], lex.goEscapes, lex.json5, lex.strictSurrogates)
+		if err != nil {
+			return lxm, lex.tokenError(err, lxm)
+		}
+		if err := skipLexeme(lex, Colon); err != nil {
+			return lxm, err
+		}
+		if child := node.find(key); child != nil {
+			child.found = true
+			err = w.walk(child)
+		} else {
+			_, _, err = parseValue(lex)
+		}
+		if err != nil {
+			return lxm, err
+		}
+		end, closed, err := separator(lex, CloseCurve)
+		if err != nil {
+			return end, err
+		} else if closed {
+			w.failMissing(node, end)
+			return end, nil
+		}
+		if lxm, err = lex.next(); err != nil {
+			return lxm, err
+		}
+	}
+	w.failMissing(node, lxm)
+	return lxm, nil
+}
+
+// walkArray walks the rest of array and returns its closing token, negative indices are resolved
+// by counting elements first like skipPathPartArray does
+func (w *valuesWalker) walkArray(node *pathNode, open Token) (Token, error) {
+	lex := w.lex
+	indices := make(map[int]*pathNode, len(node.children))
+	count := -1
+	for _, child := range node.children {
+		index, ok := pathIndex(child.part)
+		if !ok {
+			w.fail(child, lex.lexemeError(ErrorWrongPath.withTypes(Object, Array), open))
+			continue
+		}
+		if index < 0 && count < 0 {
+			saved := lex.save(open.BytePos)
+			n, err := countElements(lex)
+			if err != nil {
+				return open, err
+			}
+			lex.rewind(saved)
+			count = n
+		}
+		if index < 0 {
+			index += count
+		}
+		if index < 0 {
+			w.fail(child, lex.lexemeError(ErrorWrongPath, open))
+		} else if indices[index] == nil {
+			indices[index] = child
+		} else {
+			// [N] and [-M] address the same element
+			indices[index].merge(child)
+		}
+	}
+
+	lxm, err := lex.peek()
+	if err != nil {
+		return lxm, err
+	}
+	if lxm.Type == CloseBracket {
+		if _, err = lex.next(); err == nil {
+			w.failMissing(node, lxm)
+		}
+		return lxm, err
+	}
+	for i := 0; ; i++ {
+		if child := indices[i]; child != nil {
+			child.found = true
+			err = w.walk(child)
+		} else {
+			_, _, err = parseValue(lex)
+		}
+		if err != nil {
+			return lxm, err
+		}
+		end, closed, err := separator(lex, CloseBracket)
+		if err != nil {
+			return end, err
+		} else if closed {
+			w.failMissing(node, end)
+			return end, nil
+		}
+	}
+}
+
+// find returns the child of object node with key which is not found yet
+func (n *pathNode) find(key []byte) *pathNode {
+	for _, child := range n.children {
+		if !child.found && child.part == string(key) {
+			return child
+		}
+	}
+	return nil
+}
+
+// failMissing fails paths going into children of node which were not found till the closing token
+func (w *valuesWalker) failMissing(node *pathNode, end Token) {
+	for _, child := range node.children {
+		if !child.found {
+			w.fail(child, w.lex.lexemeError(ErrorWrongPath, end))
+		}
+	}
+}
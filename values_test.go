@@ -0,0 +1,109 @@
+package jajson_test
+
+import (
+	"testing"
+
+	"github.com/aleksandrzhukovskii/jajson"
+	"github.com/stretchr/testify/suite"
+)
+
+type ValuesSuite struct {
+	suite.Suite
+}
+
+func TestValues(t *testing.T) {
+	suite.Run(t, new(ValuesSuite))
+}
+
+func (t *ValuesSuite) TestGetValues() {
+	data := []byte(`{
+		"id": 7,
+		"user": {"name": "Ann", "tags": ["a", "b", "c"], "a\"b": true},
+		"items": [{"id": 1}, {"id": 2}, {"id": 3}],
+		"user": {"name": "duplicate"},
+		"n": null
+	}`)
+	paths := [][]string{
+		{"user", "name"},
+		{"id"},
+		{"items", "[1]", "id"},
+		{"items", "[-1]", "id"},
+		{"items", "[2]", "id"},
+		{"user", "tags", "[-3]"},
+		{"user", "tags"},
+		{"user", `a"b`},
+		{},
+		{"user"},
+		{"n"},
+		{"missing"},
+		{"user", "missing"},
+		{"items", "[3]"},
+		{"items", "[-4]"},
+		{"items", "id"},
+		{"id", "x"},
+		{"id", "[0]"},
+		{"items", "[0]", "id", "x"},
+	}
+	values := jajson.GetValues(data, paths...)
+	t.Require().Len(values, len(paths))
+	for i, path := range paths {
+		typ, val, err := jajson.GetRawValue(data, path...)
+		t.Equal(typ, values[i].Type, path)
+		t.Equal(string(val), string(values[i].Value), path)
+		if err == nil {
+			t.NoError(values[i].Err, path)
+			continue
+		}
+		t.Require().Error(values[i].Err, path)
+		t.Equal(err.Error(), values[i].Err.Error(), path)
+	}
+	t.Equal(`"Ann"`, string(values[0].Value))
+	t.Equal(`3`, string(values[3].Value))
+}
+
+func (t *ValuesSuite) TestGetValuesStopsEarly() {
+	// the rest of data is invalid but it is not scanned
+	values := jajson.GetValues([]byte(`{"a": 1, "b": {"c": 2}, "d": tru`), []string{"b", "c"}, []string{"a"})
+	t.NoError(values[0].Err)
+	t.Equal(`2`, string(values[0].Value))
+	t.NoError(values[1].Err)
+	t.Equal(`1`, string(values[1].Value))
+
+	values = jajson.GetValues([]byte(`{"a": 1, "b": {"c": 2}, "d": tru`), []string{"a"}, []string{"e"})
+	t.NoError(values[0].Err)
+	t.ErrorIs(values[1].Err, jajson.ErrorUnexpected)
+	t.Equal(jajson.Err, values[1].Type)
+}
+
+func (t *ValuesSuite) TestGetValuesErrors() {
+	t.Empty(jajson.GetValues([]byte(`{}`)))
+
+	values := jajson.GetValues(nil, []string{"a"})
+	t.ErrorIs(values[0].Err, jajson.ErrorEmptyJSON)
+
+	values = jajson.GetValues([]byte(`{"a": [1, 2}`), []string{"a", "[0]"}, []string{"a", "[5]"}, []string{"b"})
+	t.NoError(values[0].Err)
+	t.ErrorIs(values[1].Err, jajson.ErrorUnexpectedLexeme)
+	var e jajson.Error
+	t.Require().ErrorAs(values[1].Err, &e)
+	t.Equal("a[5]", e.Path())
+	t.Equal(11, e.Pos())
+	t.ErrorIs(values[2].Err, jajson.ErrorUnexpectedLexeme)
+
+	values = jajson.GetValues([]byte(`{"a": [1, 2]}`), []string{"a", "[-1]"}, []string{"a", "[1]"}, []string{"a", "b"})
+	t.Equal(`2`, string(values[0].Value))
+	t.Equal(`2`, string(values[1].Value))
+	t.ErrorIs(values[2].Err, jajson.ErrorWrongPath)
+	t.Require().ErrorAs(values[2].Err, &e)
+	t.Equal(jajson.Object, e.Expected())
+	t.Equal(jajson.Array, e.Actual())
+}
+
+func (t *ValuesSuite) TestGetValuesOptions() {
+	p := jajson.Parser{JSON5: true}
+	values := p.GetValues([]byte(`{a: {'b': 0x10,}, /* c */ c: [1,],}`), []string{"a", "b"}, []string{"c", "[0]"}, []string{"c"})
+	t.Equal(`0x10`, string(values[0].Value))
+	t.Equal(`1`, string(values[1].Value))
+	t.Equal(`[1,]`, string(values[2].Value))
+	t.Equal(jajson.Array, values[2].Type)
+}